@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+// header is the first line of every generated file, which is also used for
+// excluding previously generated files when loading a package
+const header = "// Code generated by requiredgen. DO NOT EDIT."
+
+// Generate returns the formatted source of the MarshalJSON and UnmarshalJSON
+// methods of the given struct types, in the package in the given directory
+func Generate(dir string, typeNames []string) ([]byte, error) {
+	pkg, err := load(dir)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{pkg: pkg}
+	g.printf("%s\n\npackage %s\n\n", header, pkg.Name())
+	g.printf("import (\n\t\"reflect\"\n\n")
+	g.printf("\trequiredjson \"github.com/Pungyeon/required/pkg/json\"\n")
+	g.printf("\t\"github.com/Pungyeon/required/pkg/structtag\"\n)\n")
+	for _, name := range typeNames {
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type not found: %s", name)
+		}
+		st, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok {
+			return nil, fmt.Errorf("type is not a struct: %s", name)
+		}
+		if err := g.generate(name, st); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// load will parse and type check the package in the given directory. Test
+// files and files previously generated by requiredgen are ignored.
+func load(dir string) (*types.Package, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected a single package in %s, found %d", dir, len(pkgs))
+	}
+	var (
+		name  string
+		files []*ast.File
+	)
+	for pkgName, pkg := range pkgs {
+		name = pkgName
+		for _, file := range pkg.Files {
+			if len(file.Comments) > 0 && strings.HasPrefix(file.Comments[0].Text(), strings.TrimPrefix(header, "// ")) {
+				continue
+			}
+			files = append(files, file)
+		}
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	return conf.Check(name, fset, files, nil)
+}
+
+type generator struct {
+	pkg *types.Package
+	buf bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// field describes a single struct field
+type field struct {
+	index int
+	name  string
+	key   string
+	tag   structtag.Tag
+	rules bool
+	typ   types.Type
+	// basic is the kind of the underlying basic type, which is read and
+	// written without reflection, or nil if the field must use reflection
+	basic *types.Basic
+}
+
+func (g *generator) fields(st *types.Struct) ([]field, error) {
+	var fields []field
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		if !v.Exported() {
+			continue
+		}
+		f := field{index: i, name: v.Name(), typ: v.Type()}
+		tag := reflect.StructTag(st.Tag(i))
+		if jsonTag, ok := tag.Lookup("json"); ok {
+			var err error
+			if f.tag, err = structtag.Parse(jsonTag, i); err != nil {
+				return nil, fmt.Errorf("%s: %w", v.Name(), err)
+			}
+			f.key = f.tag.FieldName
+		} else {
+			f.key = structtag.ToSnakeCase(v.Name())
+		}
+		_, f.rules = tag.Lookup("validate")
+		f.basic = g.basic(v.Type())
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// basic returns the underlying basic type of the given type, if values of
+// the type can be read and written without reflection. Named types must be
+// declared in the package being generated, and must not have any methods
+// which change how they are encoded or validated.
+func (g *generator) basic(t types.Type) *types.Basic {
+	if named, ok := t.(*types.Named); ok {
+		if named.Obj().Pkg() != g.pkg {
+			return nil
+		}
+		for _, method := range []string{"MarshalJSON", "UnmarshalJSON", "IsValueValid", "Validate"} {
+			if obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, g.pkg, method); obj != nil {
+				return nil
+			}
+		}
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil
+	}
+	switch basic.Kind() {
+	case types.String, types.Bool,
+		types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64,
+		types.Float32, types.Float64:
+		return basic
+	}
+	return nil
+}
+
+// reader returns the name of the Reader and Writer methods, and the Go type
+// of their values, for the given basic type
+func reader(basic *types.Basic) (string, string) {
+	switch basic.Info() & (types.IsString | types.IsBoolean | types.IsUnsigned | types.IsInteger | types.IsFloat) {
+	case types.IsString:
+		return "String", "string"
+	case types.IsBoolean:
+		return "Bool", "bool"
+	case types.IsInteger | types.IsUnsigned:
+		return "Uint64", "uint64"
+	case types.IsInteger:
+		return "Int64", "int64"
+	}
+	return "Float64", "float64"
+}
+
+// quote returns a Go string literal of s, which is a raw string literal if
+// possible, as the field names written by MarshalJSON are already quoted
+func quote(s string) string {
+	if strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(g.pkg))
+}
+
+func (g *generator) generate(name string, st *types.Struct) error {
+	fields, err := g.fields(st)
+	if err != nil {
+		return err
+	}
+	tags := "_" + name + "_tags"
+	g.printf("\nvar %s = structtag.MustFromValue(reflect.ValueOf(&%s{}).Elem())\n", tags, name)
+	g.generateMarshal(name, fields)
+	g.generateUnmarshal(name, tags, fields)
+	return nil
+}
+
+func (g *generator) generateMarshal(name string, fields []field) {
+	g.printf("\n// MarshalJSON is an implementation of the json.Marshaler interface\n")
+	g.printf("func (v %s) MarshalJSON() ([]byte, error) {\n", name)
+	g.printf("w := requiredjson.NewWriter()\nw.ObjectStart()\n")
+	for _, f := range fields {
+		// the key is written exactly as the reflective encoder writes it
+		key, _ := json.Marshal(f.key)
+		g.printf("w.Field(%s)\n", quote(string(key)))
+		if f.basic == nil {
+			g.printf("if err := w.Encode(&v.%s); err != nil {\nreturn nil, err\n}\n", f.name)
+			continue
+		}
+		method, typ := reader(f.basic)
+		if g.typeString(f.typ) == typ {
+			g.printf("w.%s(v.%s)\n", method, f.name)
+		} else {
+			g.printf("w.%s(%s(v.%s))\n", method, typ, f.name)
+		}
+	}
+	g.printf("w.ObjectEnd()\nreturn w.Bytes()\n}\n")
+}
+
+func (g *generator) generateUnmarshal(name, tags string, fields []field) {
+	// like the reflective decoder, the last field with a given key is used
+	decoded := map[string]field{}
+	for _, f := range fields {
+		decoded[f.key] = f
+	}
+
+	g.printf("\n// UnmarshalJSON is an implementation of the json.Unmarshaler interface\n")
+	g.printf("func (v *%s) UnmarshalJSON(data []byte) error {\n", name)
+	g.printf("r, err := requiredjson.NewReader(data)\nif err != nil {\nreturn err\n}\n")
+	g.printf("if r.IsNull() {\nreturn nil\n}\n")
+	g.printf("if err := r.ObjectStart(); err != nil {\nreturn err\n}\n")
+	g.printf("state := %s.NewState()\n", tags)
+	g.printf("for {\nkey, ok, err := r.Field()\nif err != nil {\nreturn err\n}\nif !ok {\nbreak\n}\n")
+	g.printf("switch key {\n")
+	for _, f := range fields {
+		if decoded[f.key].index != f.index {
+			continue
+		}
+		g.printf("case %s:\n", strconv.Quote(f.key))
+		g.printf("state[%d] = r.State()\n", f.index)
+		if f.basic == nil {
+			g.printf("err = r.Decode(&v.%s)\n", f.name)
+		} else {
+			method, typ := reader(f.basic)
+			g.printf("if state[%d] == structtag.Null {\nerr = r.Skip()\n", f.index)
+			if g.typeString(f.typ) == typ {
+				g.printf("} else {\nv.%s, err = r.%s()\n}\n", f.name, method)
+			} else {
+				g.printf("} else {\nvar value %s\nif value, err = r.%s(); err == nil {\nv.%s = %s(value)\n}\n}\n",
+					typ, method, f.name, g.typeString(f.typ))
+			}
+		}
+		if f.rules {
+			g.printf("if err == nil && state[%d] == structtag.Present {\n", f.index)
+			g.printf("r.Validate(key, %s.Tags[key], &v.%s)\n}\n", tags, f.name)
+		}
+	}
+	g.printf("default:\nerr = r.Skip()\n}\nif err != nil {\nreturn err\n}\n}\n")
+
+	for _, f := range fields {
+		if !f.tag.HasDefault || decoded[f.key].index != f.index {
+			continue
+		}
+		key := strconv.Quote(f.key)
+		g.printf("if state[%d] == structtag.Absent {\n", f.index)
+		g.printf("if err := r.Default(%s, %s.Tags[%s], &v.%s); err != nil {\nreturn err\n}\n}\n", key, tags, key, f.name)
+	}
+	g.printf("if err := %s.CheckRequired(reflect.ValueOf(v).Elem(), state); err != nil {\nreturn err\n}\n", tags)
+	g.printf("return r.ObjectEnd()\n}\n")
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the generated example")
+
+func TestGenerateGolden(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	golden := filepath.Join(dir, "customer_requiredgen.go")
+
+	src, err := Generate(dir, []string{"Customer", "Address"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(golden, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(expected) {
+		t.Fatalf("generated code does not match %s, run go generate or go test -update", golden)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	for _, types := range [][]string{{"Unknown"}, {"Level"}} {
+		if _, err := Generate(dir, types); err == nil {
+			t.Fatalf("expected error for %v", types)
+		}
+	}
+}
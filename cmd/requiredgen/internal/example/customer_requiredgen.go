@@ -0,0 +1,267 @@
+// Code generated by requiredgen. DO NOT EDIT.
+
+package example
+
+import (
+	"reflect"
+
+	requiredjson "github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+var _Customer_tags = structtag.MustFromValue(reflect.ValueOf(&Customer{}).Elem())
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (v Customer) MarshalJSON() ([]byte, error) {
+	w := requiredjson.NewWriter()
+	w.ObjectStart()
+	w.Field(`"id"`)
+	w.Uint64(v.ID)
+	w.Field(`"name"`)
+	w.String(v.Name)
+	w.Field(`"email"`)
+	w.String(v.Email)
+	w.Field(`"phone"`)
+	w.String(v.Phone)
+	w.Field(`"age"`)
+	w.Int64(int64(v.Age))
+	w.Field(`"level"`)
+	w.Int64(int64(v.Level))
+	w.Field(`"score"`)
+	w.Float64(float64(v.Score))
+	w.Field(`"active"`)
+	w.Bool(v.Active)
+	w.Field(`"address"`)
+	if err := w.Encode(&v.Address); err != nil {
+		return nil, err
+	}
+	w.Field(`"tags"`)
+	if err := w.Encode(&v.Tags); err != nil {
+		return nil, err
+	}
+	w.Field(`"labels"`)
+	if err := w.Encode(&v.Labels); err != nil {
+		return nil, err
+	}
+	w.Field(`"contact"`)
+	if err := w.Encode(&v.Contact); err != nil {
+		return nil, err
+	}
+	w.Field(`"nickname"`)
+	w.String(v.Nickname)
+	w.ObjectEnd()
+	return w.Bytes()
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarshaler interface
+func (v *Customer) UnmarshalJSON(data []byte) error {
+	r, err := requiredjson.NewReader(data)
+	if err != nil {
+		return err
+	}
+	if r.IsNull() {
+		return nil
+	}
+	if err := r.ObjectStart(); err != nil {
+		return err
+	}
+	state := _Customer_tags.NewState()
+	for {
+		key, ok, err := r.Field()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch key {
+		case "id":
+			state[0] = r.State()
+			if state[0] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.ID, err = r.Uint64()
+			}
+		case "name":
+			state[1] = r.State()
+			if state[1] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Name, err = r.String()
+			}
+			if err == nil && state[1] == structtag.Present {
+				r.Validate(key, _Customer_tags.Tags[key], &v.Name)
+			}
+		case "email":
+			state[2] = r.State()
+			if state[2] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Email, err = r.String()
+			}
+			if err == nil && state[2] == structtag.Present {
+				r.Validate(key, _Customer_tags.Tags[key], &v.Email)
+			}
+		case "phone":
+			state[3] = r.State()
+			if state[3] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Phone, err = r.String()
+			}
+		case "age":
+			state[4] = r.State()
+			if state[4] == structtag.Null {
+				err = r.Skip()
+			} else {
+				var value int64
+				if value, err = r.Int64(); err == nil {
+					v.Age = int(value)
+				}
+			}
+			if err == nil && state[4] == structtag.Present {
+				r.Validate(key, _Customer_tags.Tags[key], &v.Age)
+			}
+		case "level":
+			state[5] = r.State()
+			if state[5] == structtag.Null {
+				err = r.Skip()
+			} else {
+				var value int64
+				if value, err = r.Int64(); err == nil {
+					v.Level = Level(value)
+				}
+			}
+		case "score":
+			state[6] = r.State()
+			if state[6] == structtag.Null {
+				err = r.Skip()
+			} else {
+				var value float64
+				if value, err = r.Float64(); err == nil {
+					v.Score = float32(value)
+				}
+			}
+		case "active":
+			state[7] = r.State()
+			if state[7] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Active, err = r.Bool()
+			}
+		case "address":
+			state[8] = r.State()
+			err = r.Decode(&v.Address)
+		case "tags":
+			state[9] = r.State()
+			err = r.Decode(&v.Tags)
+			if err == nil && state[9] == structtag.Present {
+				r.Validate(key, _Customer_tags.Tags[key], &v.Tags)
+			}
+		case "labels":
+			state[10] = r.State()
+			err = r.Decode(&v.Labels)
+		case "contact":
+			state[11] = r.State()
+			err = r.Decode(&v.Contact)
+		case "nickname":
+			state[12] = r.State()
+			if state[12] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Nickname, err = r.String()
+			}
+		default:
+			err = r.Skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if state[5] == structtag.Absent {
+		if err := r.Default("level", _Customer_tags.Tags["level"], &v.Level); err != nil {
+			return err
+		}
+	}
+	if err := _Customer_tags.CheckRequired(reflect.ValueOf(v).Elem(), state); err != nil {
+		return err
+	}
+	return r.ObjectEnd()
+}
+
+var _Address_tags = structtag.MustFromValue(reflect.ValueOf(&Address{}).Elem())
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (v Address) MarshalJSON() ([]byte, error) {
+	w := requiredjson.NewWriter()
+	w.ObjectStart()
+	w.Field(`"street"`)
+	w.String(v.Street)
+	w.Field(`"city"`)
+	w.String(v.City)
+	w.Field(`"country"`)
+	w.String(v.Country)
+	w.ObjectEnd()
+	return w.Bytes()
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarshaler interface
+func (v *Address) UnmarshalJSON(data []byte) error {
+	r, err := requiredjson.NewReader(data)
+	if err != nil {
+		return err
+	}
+	if r.IsNull() {
+		return nil
+	}
+	if err := r.ObjectStart(); err != nil {
+		return err
+	}
+	state := _Address_tags.NewState()
+	for {
+		key, ok, err := r.Field()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		switch key {
+		case "street":
+			state[0] = r.State()
+			if state[0] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Street, err = r.String()
+			}
+		case "city":
+			state[1] = r.State()
+			if state[1] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.City, err = r.String()
+			}
+		case "country":
+			state[2] = r.State()
+			if state[2] == structtag.Null {
+				err = r.Skip()
+			} else {
+				v.Country, err = r.String()
+			}
+		default:
+			err = r.Skip()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if state[2] == structtag.Absent {
+		if err := r.Default("country", _Address_tags.Tags["country"], &v.Country); err != nil {
+			return err
+		}
+	}
+	if err := _Address_tags.CheckRequired(reflect.ValueOf(v).Elem(), state); err != nil {
+		return err
+	}
+	return r.ObjectEnd()
+}
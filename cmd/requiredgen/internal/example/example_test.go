@@ -0,0 +1,135 @@
+package example
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/required"
+)
+
+// reflectiveCustomer has the same fields and tags as Customer, but none of
+// the generated methods, so it is decoded and encoded using reflection
+type reflectiveCustomer Customer
+
+func TestGeneratedUnmarshal(t *testing.T) {
+	tt := []struct {
+		name string
+		data string
+		err  bool
+	}{
+		{"valid", `{"id": 1, "name": "lasse", "email": "lasse@jakobsen.dev", "age": 32, "level": 3, "score": 1.5, "active": true,
+			"address": {"street": "Main Street", "city": "Copenhagen"}, "tags": ["a", "b"], "labels": {"k": "v"},
+			"contact": "phone", "nickname": "pungyeon", "unknown": {"a": [1, 2]}}`, false},
+		{"defaults", `{"id": 1, "name": "lasse", "phone": "12345678"}`, false},
+		{"null", `null`, false},
+		{"null fields", `{"id": 1, "name": "lasse", "phone": "12345678", "age": null, "address": null, "tags": null}`, false},
+		{"missing required", `{"name": "lasse", "phone": "12345678"}`, true},
+		{"oneof", `{"id": 1, "name": "lasse", "phone": "12345678", "email": "lasse@jakobsen.dev"}`, true},
+		{"null notnull", `{"id": 1, "name": "lasse", "phone": "12345678", "active": null}`, true},
+		{"rules", `{"id": 1, "name": "", "email": "lasse", "age": 200, "tags": [""]}`, true},
+		{"nested", `{"id": 1, "name": "lasse", "phone": "12345678", "address": {"city": "Copenhagen"}}`, true},
+		{"wrong type", `{"id": "1", "name": "lasse"}`, true},
+		{"invalid", `{"id": 1, "name": "lasse",}`, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var generated Customer
+			genErr := json.Unmarshal([]byte(tc.data), &generated)
+			var reflective reflectiveCustomer
+			refErr := json.Unmarshal([]byte(tc.data), &reflective)
+
+			if (genErr != nil) != tc.err {
+				t.Fatalf("expected error: %v, got: %v", tc.err, genErr)
+			}
+			if (genErr == nil) != (refErr == nil) || (genErr != nil && genErr.Error() != refErr.Error()) {
+				t.Fatalf("generated error: %v, reflective error: %v", genErr, refErr)
+			}
+			if genErr == nil && !reflect.DeepEqual(generated, Customer(reflective)) {
+				t.Fatalf("generated: %+v, reflective: %+v", generated, reflective)
+			}
+		})
+	}
+}
+
+func TestGeneratedNested(t *testing.T) {
+	// the options are enforced, and the errors reported, for the whole
+	// document, even though the generated methods only see the customers
+	type generatedOrder struct {
+		Customers []Customer `json:"customers"`
+	}
+	type reflectiveOrder struct {
+		Customers []reflectiveCustomer `json:"customers"`
+	}
+	tt := []struct {
+		name string
+		opts json.Options
+		data string
+		err  string
+	}{
+		{"rules", json.Options{}, `{"customers": [{"id": 1, "name": "", "phone": "1"}]}`, "customers[0].name: min"},
+		{"duplicate", json.Options{DisallowDuplicateKeys: true}, `{"customers": [{"id": 1, "id": 2}]}`, `customers[0]: "id"`},
+		{"depth", json.Options{MaxDepth: 3}, `{"customers": [{"id": 1, "name": "a", "phone": "1", "tags": ["a"]}]}`, "customers[0].tags: max depth is 3"},
+		{"lenient", json.Options{Lenient: true}, `{customers: [{id: 1, name: 'a', phone: '1',}]}`, ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var generated generatedOrder
+			genErr := tc.opts.Unmarshal([]byte(tc.data), &generated)
+			var reflective reflectiveOrder
+			refErr := tc.opts.Unmarshal([]byte(tc.data), &reflective)
+
+			if tc.err == "" && genErr != nil || tc.err != "" && (genErr == nil || !strings.Contains(genErr.Error(), tc.err)) {
+				t.Fatalf("expected error: %q, got: %v", tc.err, genErr)
+			}
+			if (genErr == nil) != (refErr == nil) || (genErr != nil && genErr.Error() != refErr.Error()) {
+				t.Fatalf("generated error: %v, reflective error: %v", genErr, refErr)
+			}
+		})
+	}
+}
+
+func TestGeneratedMarshal(t *testing.T) {
+	customer := Customer{
+		ID:       1,
+		Name:     "lasse",
+		Age:      32,
+		Level:    -1,
+		Score:    1.5,
+		Active:   true,
+		Address:  &Address{Street: "Main Street", City: "Copenhagen"},
+		Tags:     []string{"a"},
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Contact:  required.NewString("phone"),
+		Nickname: "pungyeon",
+		internal: "hidden",
+	}
+	nan, inf := customer, customer
+	nan.Score = float32(math.NaN())
+	inf.Score = float32(math.Inf(-1))
+	for _, v := range []Customer{customer, {}, nan, inf} {
+		generated, genErr := json.Marshal(v)
+		reflective, refErr := json.Marshal(reflectiveCustomer(v))
+		if (genErr == nil) != (refErr == nil) || (genErr != nil && genErr.Error() != refErr.Error()) {
+			t.Fatalf("generated error: %v, reflective error: %v", genErr, refErr)
+		}
+		if string(generated) != string(reflective) {
+			t.Fatalf("\ngenerated:  %s\nreflective: %s", generated, reflective)
+		}
+	}
+
+	address := Address{Street: "Main Street", City: "Copenhagen", Country: "SE"}
+	data, err := json.Marshal(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Address
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != address {
+		t.Fatalf("decoded: %+v, expected: %+v", decoded, address)
+	}
+}
@@ -0,0 +1,32 @@
+// Package example contains types with methods generated by requiredgen,
+// which are tested against the reflective decoder and encoder.
+package example
+
+import "github.com/Pungyeon/required/pkg/required"
+
+//go:generate go run github.com/Pungyeon/required/cmd/requiredgen -type Customer,Address
+
+type Level int8
+
+type Customer struct {
+	ID       uint64            `json:"id,required"`
+	Name     string            `json:"name,required" validate:"min=1,max=64"`
+	Email    string            `json:"email,oneof=contact" validate:"email"`
+	Phone    string            `json:"phone,oneof=contact"`
+	Age      int               `json:"age" validate:"gte=0,lt=150"`
+	Level    Level             `json:"level,default=1"`
+	Score    float32           `json:"score"`
+	Active   bool              `json:"active,notnull"`
+	Address  *Address          `json:"address"`
+	Tags     []string          `json:"tags" validate:"dive,min=1"`
+	Labels   map[string]string `json:"labels"`
+	Contact  required.String   `json:"contact"`
+	Nickname string
+	internal string
+}
+
+type Address struct {
+	Street  string `json:"street,required"`
+	City    string `json:"city,required_with=street"`
+	Country string `json:"country,default=DK"`
+}
@@ -0,0 +1,70 @@
+// Command requiredgen generates MarshalJSON and UnmarshalJSON methods for
+// struct types, which enforce the same struct tags as the json package.
+// Fields of basic types, such as strings, numbers and booleans, are read
+// and written without reflection. Everything else is still reflective:
+//
+//   - fields of any other type, such as slices, maps, pointers and types
+//     implementing json.Marshaler, are encoded and decoded by the json
+//     package, through Writer.Encode and Reader.Decode
+//   - the `validate` rules of a field, and its `default` value, are applied
+//     by the json package, through Reader.Validate and Reader.Default
+//   - the required options and conditions of the json tag are checked by
+//     Tags.CheckRequired, on the reflect.Value of the decoded struct
+//   - the Tags of each type are parsed once, when the package is
+//     initialised, with structtag.MustFromValue
+//
+// It is intended to be used with go:generate:
+//
+//	//go:generate go run github.com/Pungyeon/required/cmd/requiredgen -type Customer,Address
+//
+// By default the methods are written to <type>_requiredgen.go, in the
+// directory of the package.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of type names; must be set")
+	output    = flag.String("output", "", "output file name; default <dir>/<type>_requiredgen.go")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of requiredgen:\n")
+	fmt.Fprintf(os.Stderr, "\trequiredgen -type T [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	src, err := Generate(dir, types)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "requiredgen:", err)
+		os.Exit(1)
+	}
+	name := *output
+	if name == "" {
+		name = filepath.Join(dir, strings.ToLower(types[0])+"_requiredgen.go")
+	}
+	if err := os.WriteFile(name, src, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "requiredgen:", err)
+		os.Exit(1)
+	}
+}
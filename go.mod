@@ -1,5 +1,5 @@
 module github.com/Pungyeon/required
 
-go 1.15
+go 1.18
 
 require github.com/stretchr/testify v1.8.0
@@ -0,0 +1,253 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/structtag"
+	"github.com/Pungyeon/required/pkg/token"
+	"github.com/Pungyeon/required/pkg/validate"
+)
+
+// Reader reads a single JSON object, one field at a time. It is used by the
+// UnmarshalJSON methods generated by cmd/requiredgen, and decodes values in
+// the same way as Unmarshal with the default Options.
+//
+// A Reader only sees the raw object, so it does not know the Options or the
+// path of the object. When the object is decoded as part of a document, the
+// decoder enforces the limits, DisallowDuplicateKeys and Lenient options on
+// the raw object before handing it over, and prefixes the paths of any
+// validation errors with the path of the object.
+type Reader struct {
+	p     parser
+	first bool
+}
+
+// NewReader returns a Reader, positioned at the first token of the data
+func NewReader(data []byte) (*Reader, error) {
+	r := &Reader{p: parser{lexer: lexer.NewLexer(data)}}
+	if err := r.p.next(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// IsNull returns whether the current value is null
+func (r *Reader) IsNull() bool {
+	return r.p.current.Type == token.Null
+}
+
+// State returns the state of the current field value, which is either
+// structtag.Null or structtag.Present
+func (r *Reader) State() structtag.FieldState {
+	if r.IsNull() {
+		return structtag.Null
+	}
+	return structtag.Present
+}
+
+// ObjectStart will read the opening curly brace of an object
+func (r *Reader) ObjectStart() error {
+	if r.p.current.Type != token.OpenCurly {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object, got: %s", r.p.current))
+	}
+	r.first = true
+	return r.p.next()
+}
+
+// Field will read the name of the next field of the object, leaving the
+// Reader at the value of the field. If there are no more fields, false is
+// returned.
+func (r *Reader) Field() (string, bool, error) {
+	if !r.first {
+		if err := r.p.separator(token.ClosingCurly); err != nil {
+			return "", false, err
+		}
+	}
+	r.first = false
+	if r.p.current.Type == token.ClosingCurly {
+		return "", false, nil
+	}
+	field, err := r.p.member()
+	if err != nil {
+		return "", false, err
+	}
+	r.p.push(segment{field: field.ToString()})
+	return field.ToString(), true, nil
+}
+
+// ObjectEnd will read the closing curly brace of the object, which must be
+// the end of the data. Any validation errors found while reading the object
+// are returned.
+func (r *Reader) ObjectEnd() error {
+	if r.p.current.Type != token.ClosingCurly {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected end of object, got: %s", r.p.current))
+	}
+	if err := checkIfEOF(r.p.next()); err != nil {
+		return err
+	}
+	if r.p.current.Type != token.Unknown {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected data after object: %s", r.p.current))
+	}
+	if len(r.p.errs) > 0 {
+		return r.p.errs
+	}
+	return nil
+}
+
+// done will remove the current field from the path, once its value has been
+// read
+func (r *Reader) done() {
+	if len(r.p.path) > 0 {
+		r.p.pop()
+	}
+}
+
+// String reads the value of the current field as a string
+func (r *Reader) String() (string, error) {
+	defer r.done()
+	return r.p.readString()
+}
+
+// Int64 reads the value of the current field as a signed integer
+func (r *Reader) Int64() (int64, error) {
+	defer r.done()
+	return r.p.readInt()
+}
+
+// Uint64 reads the value of the current field as an unsigned integer
+func (r *Reader) Uint64() (uint64, error) {
+	defer r.done()
+	return r.p.readUint()
+}
+
+// Float64 reads the value of the current field as a floating point number
+func (r *Reader) Float64() (float64, error) {
+	defer r.done()
+	return r.p.readFloat()
+}
+
+// Bool reads the value of the current field as a boolean
+func (r *Reader) Bool() (bool, error) {
+	defer r.done()
+	return r.p.readBool()
+}
+
+// Skip will skip the value of the current field
+func (r *Reader) Skip() error {
+	defer r.done()
+	_, err := r.p.skip()
+	return err
+}
+
+// Decode will decode the value of the current field into the value pointed
+// to by v, as Unmarshal would.
+func (r *Reader) Decode(v interface{}) error {
+	defer r.done()
+	return r.p.decode(reflect.ValueOf(v).Elem())
+}
+
+// Default will decode the default value of the given tag into the value
+// pointed to by v
+func (r *Reader) Default(key string, tag structtag.Tag, v interface{}) error {
+	r.p.push(segment{field: key})
+	defer r.p.pop()
+	return r.p.decodeRaw(reflect.ValueOf(v).Elem(), tag.Default)
+}
+
+// Validate will validate the value pointed to by v, which has been read from
+// the field with the given key, using the rules of the given tag. Any errors
+// are returned by ObjectEnd.
+func (r *Reader) Validate(key string, tag structtag.Tag, v interface{}) {
+	if tag.Rules.IsEmpty() {
+		return
+	}
+	r.p.push(segment{field: key})
+	defer r.p.pop()
+	r.p.invalid(tag.Rules.Validate(reflect.ValueOf(v).Elem()).Prefix(r.p.Path()))
+}
+
+// Errors returns the validation errors, which have been found so far
+func (r *Reader) Errors() validate.Errors {
+	return r.p.errs
+}
+
+// Writer writes a single JSON object, one field at a time. It is used by the
+// MarshalJSON methods generated by cmd/requiredgen, and encodes values in
+// exactly the same way as Marshal.
+type Writer struct {
+	buf bytes.Buffer
+	err error
+}
+
+// NewWriter returns an empty Writer
+func NewWriter() *Writer {
+	return &Writer{}
+}
+
+// ObjectStart will write the opening curly brace of an object
+func (w *Writer) ObjectStart() {
+	w.buf.WriteByte('{')
+}
+
+// Field will write the name of a field, which must already be quoted
+func (w *Writer) Field(name string) {
+	if b := w.buf.Bytes(); len(b) > 0 && b[len(b)-1] != '{' {
+		w.buf.WriteByte(',')
+	}
+	w.buf.WriteString(name)
+	w.buf.WriteRune(colon)
+}
+
+// ObjectEnd will write the closing curly brace of an object
+func (w *Writer) ObjectEnd() {
+	w.buf.WriteByte('}')
+}
+
+// String writes a string value
+func (w *Writer) String(s string) {
+	writeString(&w.buf, s)
+}
+
+// Int64 writes a signed integer value
+func (w *Writer) Int64(n int64) {
+	writeInt(&w.buf, n)
+}
+
+// Uint64 writes an unsigned integer value
+func (w *Writer) Uint64(n uint64) {
+	writeUint(&w.buf, n)
+}
+
+// Float64 writes a floating point value. Infinite and NaN values cannot be
+// represented as JSON, and cause Bytes to return ErrUnsupportedValue.
+func (w *Writer) Float64(f float64) {
+	if err := checkFloat(f); err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return
+	}
+	writeFloat(&w.buf, f)
+}
+
+// Bool writes a boolean value
+func (w *Writer) Bool(b bool) {
+	writeBool(&w.buf, b)
+}
+
+// Encode will write the value pointed to by v, as Marshal would
+func (w *Writer) Encode(v interface{}) error {
+	return _marshal(reflect.ValueOf(v), &w.buf)
+}
+
+// Bytes returns the written JSON, or the first error of a value which
+// could not be written
+func (w *Writer) Bytes() ([]byte, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	return w.buf.Bytes(), nil
+}
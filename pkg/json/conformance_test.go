@@ -0,0 +1,211 @@
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// implementationDefined is the decision table of the i_ cases of the
+// JSONTestSuite, for which RFC 8259 allows both accepting and rejecting the
+// document. true means that the document is accepted.
+var implementationDefined = map[string]bool{
+	// numbers which underflow are decoded as zero, like the standard library
+	"i_number_double_huge_neg_exp.json": true,
+	"i_number_real_underflow.json":      true,
+	// numbers which overflow a float64 are valid JSON, so they are accepted
+	// as raw and skipped values, but cannot be decoded, like the standard
+	// library (see outOfRange)
+	"i_number_huge_exp.json":            true,
+	"i_number_neg_int_huge_exp.json":    true,
+	"i_number_pos_double_huge_exp.json": true,
+	"i_number_real_neg_overflow.json":   true,
+	"i_number_real_pos_overflow.json":   true,
+	// integers which overflow an int64 are decoded as a float64 when the
+	// type is unknown, like the standard library
+	"i_number_too_big_neg_int.json":       true,
+	"i_number_too_big_pos_int.json":       true,
+	"i_number_very_big_negative_int.json": true,
+	// escaped surrogates, which are not part of a valid pair, are decoded as
+	// utf8.RuneError, like the standard library
+	"i_object_key_lone_2nd_surrogate.json":                true,
+	"i_string_1st_surrogate_but_2nd_missing.json":         true,
+	"i_string_1st_valid_surrogate_2nd_invalid.json":       true,
+	"i_string_incomplete_surrogate_and_escape_valid.json": true,
+	"i_string_incomplete_surrogate_pair.json":             true,
+	"i_string_incomplete_surrogates_escape_valid.json":    true,
+	"i_string_invalid_lonely_surrogate.json":              true,
+	"i_string_invalid_surrogate.json":                     true,
+	"i_string_inverted_surrogates_U+1D11E.json":           true,
+	"i_string_lone_second_surrogate.json":                 true,
+	// invalid UTF-8 within strings is accepted and kept as is, so strings
+	// are never copied for validating or replacing invalid bytes
+	"i_string_UTF-8_invalid_sequence.json":         true,
+	"i_string_UTF8_surrogate_U+D800.json":          true,
+	"i_string_invalid_utf-8.json":                  true,
+	"i_string_iso_latin_1.json":                    true,
+	"i_string_lone_utf8_continuation_byte.json":    true,
+	"i_string_not_in_unicode_range.json":           true,
+	"i_string_overlong_sequence_2_bytes.json":      true,
+	"i_string_overlong_sequence_6_bytes.json":      true,
+	"i_string_overlong_sequence_6_bytes_null.json": true,
+	"i_string_truncated-utf-8.json":                true,
+	// only UTF-8 is supported, and byte order marks are rejected
+	"i_string_UTF-16LE_with_BOM.json":         false,
+	"i_string_utf16BE_no_BOM.json":            false,
+	"i_string_utf16LE_no_BOM.json":            false,
+	"i_structure_UTF-8_BOM_empty_object.json": false,
+	// nesting is only limited by Options.MaxDepth
+	"i_structure_500_nested_arrays.json": true,
+}
+
+// outOfRange are the documents, which are valid, but contain numbers which
+// overflow a float64, so they are rejected by the decoders which decode them
+var outOfRange = map[string]bool{
+	"i_number_huge_exp.json":            true,
+	"i_number_neg_int_huge_exp.json":    true,
+	"i_number_pos_double_huge_exp.json": true,
+	"i_number_real_neg_overflow.json":   true,
+	"i_number_real_pos_overflow.json":   true,
+}
+
+// decoded are the decoders, which decode every value of the document, rather
+// than only validating it
+var decoded = map[string]bool{"interface": true, "ordered": true, "tree": true}
+
+// decoders are the ways a document can be decoded, which must all agree on
+// whether a document is valid
+var decoders = map[string]func(data []byte) error{
+	"interface": func(data []byte) error {
+		var v interface{}
+		return Unmarshal(data, &v)
+	},
+	"ordered": func(data []byte) error {
+		var v interface{}
+		return Options{OrderedObjects: true}.Unmarshal(data, &v)
+	},
+	"raw": func(data []byte) error {
+		var v RawMessage
+		return Unmarshal(data, &v)
+	},
+	"unknown field": func(data []byte) error {
+		var v struct{}
+		return Unmarshal(append(append([]byte(`{"unknown": `), data...), '}'), &v)
+	},
+	"tree": func(data []byte) error {
+		_, err := ParseTree(data)
+		return err
+	},
+}
+
+func TestConformance(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "JSONTestSuite", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no test files found")
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var accept bool
+		switch {
+		case strings.HasPrefix(name, "y_"):
+			accept = true
+		case strings.HasPrefix(name, "n_"):
+			accept = false
+		case strings.HasPrefix(name, "i_"):
+			var ok bool
+			if accept, ok = implementationDefined[name]; !ok {
+				t.Fatalf("%s: missing from the decision table", name)
+			}
+		default:
+			t.Fatalf("%s: unexpected test file", name)
+		}
+		for decoder, decode := range decoders {
+			accepted := accept && !(outOfRange[name] && decoded[decoder])
+			if err := decode(data); (err == nil) != accepted {
+				t.Errorf("%s: %s: expected accepted: %v, got: %v", name, decoder, accepted, err)
+			}
+		}
+	}
+}
+
+// TestConformanceValues checks, that every y_ document is decoded to the same
+// value as the standard library, and can be marshalled and decoded again
+func TestConformanceValues(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "JSONTestSuite", "y_*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expected interface{}
+		if err := stdjson.Unmarshal(data, &expected); err != nil {
+			t.Fatal(err)
+		}
+		var v interface{}
+		if err := Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		out, err := Marshal(v)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		var actual interface{}
+		if err := stdjson.Unmarshal(out, &actual); err != nil {
+			t.Fatalf("%s: invalid output: %s: %v", file, out, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("%s: expected: %#v, got: %#v", file, expected, actual)
+		}
+	}
+}
+
+func TestConformanceDeepNesting(t *testing.T) {
+	for _, data := range [][]byte{
+		bytes.Repeat([]byte("["), 100000),
+		bytes.Repeat([]byte(`[{"":`), 50000),
+	} {
+		for decoder, decode := range decoders {
+			if err := decode(data); err == nil {
+				t.Errorf("%s: expected error for unclosed nesting", decoder)
+			}
+		}
+	}
+	data := append(bytes.Repeat([]byte("["), 100000), bytes.Repeat([]byte("]"), 100000)...)
+	for decoder, decode := range decoders {
+		if err := decode(data); err != nil {
+			t.Errorf("%s: %v", decoder, err)
+		}
+	}
+}
+
+// FuzzConformance checks, that exactly the same documents are valid as for
+// the standard library
+func FuzzConformance(f *testing.F) {
+	for _, seed := range []string{`{"a": [1, -2.5e3, "é"]}`, `[1,]`, `tru`, `"\x"`, `{"a":1}}`, ` null `} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var raw RawMessage
+		err := Unmarshal(data, &raw)
+		if valid := stdjson.Valid(data); valid != (err == nil) {
+			t.Fatalf("%q: expected valid: %v, got: %v", data, valid, err)
+		}
+		if err == nil && !bytes.Equal(raw, bytes.TrimSpace(data)) {
+			t.Fatalf("expected raw message %q, got %q", bytes.TrimSpace(data), raw)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// ErrDuplicateKey is returned when decoding an object with duplicate keys,
+// if the DisallowDuplicateKeys option is set
+var ErrDuplicateKey = errors.New("(required::json) duplicate key")
+
+type duplicateKeyErr struct {
+	path string
+	key  string
+}
+
+func (err duplicateKeyErr) Error() string {
+	path := err.path
+	if path == "" {
+		path = "$"
+	}
+	return fmt.Sprintf("%v: %s: %q", ErrDuplicateKey, path, err.key)
+}
+
+func (err duplicateKeyErr) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// keys are the keys of the object currently being decoded, which are only
+// recorded if duplicate keys are disallowed
+type keys map[string]struct{}
+
+// unique will return an error, if duplicate keys are disallowed, and the
+// given key has already been seen in the object currently being decoded
+func (p *parser) unique(seen *keys, key []byte) error {
+	if !p.opts.DisallowDuplicateKeys {
+		return nil
+	}
+	if *seen == nil {
+		*seen = keys{}
+	}
+	if _, ok := (*seen)[string(key)]; ok {
+		return duplicateKeyErr{path: p.Path(), key: string(key)}
+	}
+	(*seen)[string(key)] = struct{}{}
+	return nil
+}
+
+// checkSkipped will return an error, if duplicate keys are disallowed, and
+// any object of the given raw value, which has been skipped rather than
+// decoded, has duplicate keys, or if the value exceeds any of the limits
+func (p *parser) checkSkipped(data []byte) error {
+	if !p.opts.DisallowDuplicateKeys && !p.opts.limited() {
+		return nil
+	}
+	d := &parser{lexer: lexer.NewLexer(data), path: p.path, opts: p.opts, depth: p.depth}
+	d.lexer.SetLenient(p.lexer.Lenient())
+	if err := d.next(); err != nil {
+		return err
+	}
+	_, err := d.value()
+	return err
+}
+
+// skipMember will skip the value of the given unknown member of an object.
+// The path of the member is only needed for reporting duplicate keys or
+// exceeded limits within the value, so it is not recorded otherwise.
+func (p *parser) skipMember(field token.Token) error {
+	if p.opts.DisallowDuplicateKeys || p.opts.limited() {
+		p.push(segment{field: field.ToString()})
+		defer p.pop()
+	}
+	_, err := p.skip()
+	return err
+}
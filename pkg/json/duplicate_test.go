@@ -0,0 +1,57 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDuplicateKeys(t *testing.T) {
+	type Claims struct {
+		Subject string                 `json:"sub,required"`
+		Admin   bool                   `json:"admin"`
+		Scopes  map[string]bool        `json:"scopes"`
+		Extra   interface{}            `json:"extra"`
+		Raw     RawMessage             `json:"raw"`
+		Nested  []map[string]string    `json:"nested"`
+		Untyped map[string]interface{} `json:"untyped"`
+	}
+	tt := []struct {
+		data string
+		msg  string
+	}{
+		{`{"sub": "a", "admin": false, "admin": true}`, `$: "admin"`},
+		{`{"sub": "a", "unknown": 1, "unknown": 2}`, `$: "unknown"`},
+		{`{"sub": "a", "scopes": {"read": true, "read": false}}`, `scopes: "read"`},
+		{`{"sub": "a", "extra": {"a": [{"b": 1, "b": 2}]}}`, `extra.a[0]: "b"`},
+		{`{"sub": "a", "raw": {"x": {"y": 1, "y": 1}}}`, `raw.x: "y"`},
+		{`{"sub": "a", "unknown": [{"k": 1, "k": 2}]}`, `unknown[0]: "k"`},
+		{`{"sub": "a", "nested": [{}, {"k": "1", "k": "2"}]}`, `nested[1]: "k"`},
+		{`{"sub": "a", "untyped": {"k": {"z": 1, "z": 2}}}`, `untyped.k: "z"`},
+	}
+	strict := Options{DisallowDuplicateKeys: true}
+	for _, tc := range tt {
+		var claims Claims
+		if err := Unmarshal([]byte(tc.data), &claims); err != nil {
+			t.Fatalf("%s: duplicate keys must be allowed by default: %v", tc.data, err)
+		}
+		err := strict.Unmarshal([]byte(tc.data), &claims)
+		if !errors.Is(err, ErrDuplicateKey) {
+			t.Fatalf("%s: expected duplicate key, got: %v", tc.data, err)
+		}
+		if expected := ErrDuplicateKey.Error() + ": " + tc.msg; err.Error() != expected {
+			t.Fatalf("expected: %s\ngot:      %s", expected, err)
+		}
+	}
+
+	var claims Claims
+	if err := strict.Unmarshal([]byte(`{"sub": "a", "extra": {"a": 1, "b": {"a": 2}}, "scopes": {"a": true}}`), &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	var v interface{}
+	for _, opts := range []Options{strict, {DisallowDuplicateKeys: true, OrderedObjects: true}} {
+		if err := opts.Unmarshal([]byte(`[{"a": 1}, {"a": 1, "a": 2}]`), &v); !errors.Is(err, ErrDuplicateKey) {
+			t.Fatalf("expected duplicate key, got: %v", err)
+		}
+	}
+}
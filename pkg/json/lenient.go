@@ -0,0 +1,54 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// skipJSON will skip the current value like skip, but in the lenient mode,
+// the value is converted into JSON, for values which are decoded from their
+// raw bytes, such as by a json.Unmarshaler
+func (p *parser) skipJSON() ([]byte, error) {
+	data, err := p.skip()
+	if err != nil || !p.lexer.Lenient() {
+		return data, err
+	}
+	return toJSON(data)
+}
+
+// toJSON converts a value of the lenient mode into JSON, so that skipped
+// values are always valid JSON. Infinity and NaN cannot be represented as
+// JSON, so these can only be decoded directly into floating point numbers.
+func toJSON(data []byte) ([]byte, error) {
+	l := lexer.NewLexer(data)
+	l.SetLenient(true)
+	var buf bytes.Buffer
+	for {
+		t, err := l.Scan()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch value := l.Bytes(); t {
+		case token.String:
+			writeString(&buf, string(value))
+		case token.Float:
+			if bytes.HasSuffix(value, lexer.INFINITY) || bytes.HasSuffix(value, lexer.NAN) {
+				return nil, token.Error(token.ErrInvalidValue, fmt.Sprintf("%s cannot be represented as JSON", value))
+			}
+			buf.Write(value)
+		default:
+			buf.Write(value)
+		}
+	}
+	if err := validRaw(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
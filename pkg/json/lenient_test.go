@@ -0,0 +1,96 @@
+package json
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/required"
+)
+
+type Config struct {
+	Name     required.String        `json:"name"`
+	Port     int                    `json:"port,required" validate:"gte=1,lte=65535"`
+	Mask     uint32                 `json:"mask"`
+	Ratio    float64                `json:"ratio"`
+	Hosts    []string               `json:"hosts"`
+	Labels   map[string]string      `json:"labels"`
+	Extra    RawMessage             `json:"extra"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+func TestLenient(t *testing.T) {
+	data := []byte(`// service configuration
+	{
+		name: 'api',
+		port: +8080, /* the public port */
+		mask: 0xFF00,
+		ratio: Infinity,
+		hosts: ['a.example.com', "b.example.com",],
+		labels: {env: 'prod', 'team': "core",},
+		extra: {nested: ['it\'s', 0x10,],},
+		settings: {limit: -0x10, enabled: true,},
+		unknown: [/* skipped */ NaN, {a: 1,},],
+	}`)
+	var config Config
+	if err := (Options{Lenient: true}).Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	expected := Config{
+		Name:     required.NewString("api"),
+		Port:     8080,
+		Mask:     0xFF00,
+		Ratio:    math.Inf(1),
+		Hosts:    []string{"a.example.com", "b.example.com"},
+		Labels:   map[string]string{"env": "prod", "team": "core"},
+		Extra:    RawMessage(`{"nested":["it's",16]}`),
+		Settings: map[string]interface{}{"limit": -16, "enabled": true},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected: %+v\ngot:      %+v", expected, config)
+	}
+
+	if err := Unmarshal(data, &config); err == nil {
+		t.Fatal("expected error, when not lenient")
+	}
+}
+
+func TestLenientValidation(t *testing.T) {
+	tt := []struct {
+		data string
+		msg  string
+	}{
+		{`{name: 'api', /* port: 80 */}`, "RequiredInterface field missing: port"},
+		{`{name: '', port: 80}`, "required.String"},
+		{`{name: 'api', port: 0x10000}`, "port"},
+		{`{name: 'api', port: 80, extra: NaN}`, "NaN cannot be represented as JSON"},
+	}
+	opts := Options{Lenient: true}
+	for _, tc := range tt {
+		var config Config
+		err := opts.Unmarshal([]byte(tc.data), &config)
+		if err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Fatalf("%s: expected %q, got: %v", tc.data, tc.msg, err)
+		}
+	}
+	for _, data := range []string{`[,]`, `{,}`, `[1,,]`, `{a: 1,,}`, `[1 2]`, `{a 1}`} {
+		var v interface{}
+		if err := opts.Unmarshal([]byte(data), &v); err == nil {
+			t.Fatalf("%s: expected error", data)
+		}
+	}
+}
+
+func TestLenientDecoder(t *testing.T) {
+	dec := NewDecoder(bytes.NewBufferString(`{name: 'api', port: 80,} // end`))
+	dec.SetOptions(Options{Lenient: true})
+	var config Config
+	if err := dec.Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Port != 80 || config.Name.Value() != "api" {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+}
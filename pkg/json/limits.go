@@ -0,0 +1,106 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is returned when decoding a document, which exceeds any
+// of the limits of the Options. The error is a *LimitError, describing which
+// limit was exceeded, and where.
+var ErrLimitExceeded = errors.New("(required::json) limit exceeded")
+
+// Limit is the name of a limit of the Options
+type Limit string
+
+const (
+	LimitBytes         Limit = "bytes"
+	LimitDepth         Limit = "depth"
+	LimitStringLength  Limit = "string length"
+	LimitArrayLength   Limit = "array length"
+	LimitObjectMembers Limit = "object members"
+)
+
+// LimitError is returned when a limit of the Options is exceeded
+type LimitError struct {
+	// Limit is the limit, which has been exceeded
+	Limit Limit
+	// Max is the configured maximum of the limit
+	Max int
+	// Path is the JSON path of the value, which exceeds the limit
+	Path string
+}
+
+func (err *LimitError) Error() string {
+	path := err.Path
+	if path == "" {
+		path = "$"
+	}
+	return fmt.Sprintf("%v: %s: max %s is %d", ErrLimitExceeded, path, err.Limit, err.Max)
+}
+
+func (err *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+func (p *parser) limitErr(limit Limit, max int) error {
+	return &LimitError{Limit: limit, Max: max, Path: p.Path()}
+}
+
+// limited returns whether any of the limits, which must be checked within
+// skipped values, are set
+func (opts Options) limited() bool {
+	return opts.MaxDepth > 0 || opts.MaxStringLength > 0 ||
+		opts.MaxArrayLength > 0 || opts.MaxObjectMembers > 0
+}
+
+// checkBytes will return an error, if the given document exceeds the
+// maximum number of bytes
+func (opts Options) checkBytes(data []byte) error {
+	if opts.MaxBytes > 0 && len(data) > opts.MaxBytes {
+		return &LimitError{Limit: LimitBytes, Max: opts.MaxBytes}
+	}
+	return nil
+}
+
+// enter will increase the depth of the parser, when decoding an object or an
+// array, returning an error if the maximum depth is exceeded. Every call must
+// be followed by a call to leave.
+func (p *parser) enter() error {
+	p.depth++
+	if p.opts.MaxDepth > 0 && p.depth > p.opts.MaxDepth {
+		return p.limitErr(LimitDepth, p.opts.MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// checkString will return an error, if the current token is a string
+// exceeding the maximum string length
+func (p *parser) checkString() error {
+	if p.opts.MaxStringLength > 0 && len(p.current.Value) > p.opts.MaxStringLength {
+		return p.limitErr(LimitStringLength, p.opts.MaxStringLength)
+	}
+	return nil
+}
+
+// checkArray will return an error, if an array has more than the maximum
+// number of elements, once its element at index i has been read
+func (p *parser) checkArray(i int) error {
+	if p.opts.MaxArrayLength > 0 && i >= p.opts.MaxArrayLength {
+		return p.limitErr(LimitArrayLength, p.opts.MaxArrayLength)
+	}
+	return nil
+}
+
+// checkMembers will return an error, if an object has more than the maximum
+// number of members, once n members have been read
+func (p *parser) checkMembers(n int) error {
+	if p.opts.MaxObjectMembers > 0 && n > p.opts.MaxObjectMembers {
+		return p.limitErr(LimitObjectMembers, p.opts.MaxObjectMembers)
+	}
+	return nil
+}
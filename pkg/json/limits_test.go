@@ -0,0 +1,111 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	type Document struct {
+		Name    string                 `json:"name"`
+		Tags    []string               `json:"tags"`
+		Matrix  [][]int                `json:"matrix"`
+		Labels  map[string]string      `json:"labels"`
+		Extra   interface{}            `json:"extra"`
+		Raw     RawMessage             `json:"raw"`
+		Untyped map[string]interface{} `json:"untyped"`
+	}
+	tt := []struct {
+		opts  Options
+		data  string
+		limit Limit
+		path  string
+	}{
+		{Options{MaxBytes: 10}, `{"name": "document"}`, LimitBytes, ""},
+		{Options{MaxDepth: 2}, `{"matrix": [[1]]}`, LimitDepth, "matrix[0]"},
+		{Options{MaxDepth: 2}, `{"extra": {"a": [1]}}`, LimitDepth, "extra.a"},
+		{Options{MaxDepth: 3}, `{"unknown": [[[[]]]]}`, LimitDepth, "unknown[0][0]"},
+		{Options{MaxDepth: 2}, `{"raw": [[1]]}`, LimitDepth, "raw[0]"},
+		{Options{MaxStringLength: 4}, `{"name": "names"}`, LimitStringLength, "name"},
+		{Options{MaxStringLength: 4}, `{"tags": ["abcd", "abcde"]}`, LimitStringLength, "tags[1]"},
+		{Options{MaxStringLength: 6}, `{"labels": {"longest": "a"}}`, LimitStringLength, "labels"},
+		{Options{MaxStringLength: 6}, `{"extra": {"a": "longest"}}`, LimitStringLength, "extra.a"},
+		{Options{MaxStringLength: 6}, `{"other": ["longest"]}`, LimitStringLength, "other[0]"},
+		{Options{MaxArrayLength: 2}, `{"tags": ["a", "b", "c"]}`, LimitArrayLength, "tags"},
+		{Options{MaxArrayLength: 2}, `{"extra": [1, 2, 3]}`, LimitArrayLength, "extra"},
+		{Options{MaxArrayLength: 2}, `{"unknown": {"a": [1, 2, 3]}}`, LimitArrayLength, "unknown.a"},
+		{Options{MaxObjectMembers: 2}, `{"name": "a", "tags": [], "labels": {}}`, LimitObjectMembers, ""},
+		{Options{MaxObjectMembers: 2}, `{"labels": {"a": "1", "b": "2", "c": "3"}}`, LimitObjectMembers, "labels"},
+		{Options{MaxObjectMembers: 1}, `{"extra": {"a": 1, "b": 2}}`, LimitObjectMembers, "extra"},
+		{Options{MaxObjectMembers: 1, OrderedObjects: true}, `{"extra": {"a": 1, "b": 2}}`, LimitObjectMembers, "extra"},
+	}
+	for _, tc := range tt {
+		var doc Document
+		if err := Unmarshal([]byte(tc.data), &doc); err != nil {
+			t.Fatalf("%s: must be decoded without limits: %v", tc.data, err)
+		}
+		err := tc.opts.Unmarshal([]byte(tc.data), &doc)
+		if !errors.Is(err, ErrLimitExceeded) {
+			t.Fatalf("%s: expected limit exceeded, got: %v", tc.data, err)
+		}
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("%s: expected *LimitError, got: %T", tc.data, err)
+		}
+		if limitErr.Limit != tc.limit || limitErr.Path != tc.path {
+			t.Fatalf("%s: expected %s at %q, got: %s at %q", tc.data, tc.limit, tc.path, limitErr.Limit, limitErr.Path)
+		}
+	}
+
+	opts := Options{MaxBytes: 64, MaxDepth: 3, MaxStringLength: 8, MaxArrayLength: 2, MaxObjectMembers: 3}
+	var doc Document
+	if err := opts.Unmarshal([]byte(`{"name": "document", "matrix": [[1, 2]], "extra": {"a": [1]}}`), &doc); err != nil {
+		t.Fatalf("document within the limits must be decoded: %v", err)
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	err := &LimitError{Limit: LimitDepth, Max: 2, Path: "a.b[0]"}
+	if expected := "(required::json) limit exceeded: a.b[0]: max depth is 2"; err.Error() != expected {
+		t.Fatalf("expected: %s\ngot:      %s", expected, err)
+	}
+	err = &LimitError{Limit: LimitBytes, Max: 10}
+	if expected := "(required::json) limit exceeded: $: max bytes is 10"; err.Error() != expected {
+		t.Fatalf("expected: %s\ngot:      %s", expected, err)
+	}
+}
+
+// endless is a reader of endlessly nested JSON arrays
+type endless struct {
+	read int
+}
+
+func (r *endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = '['
+	}
+	r.read += len(p)
+	return len(p), nil
+}
+
+func TestDecoderLimits(t *testing.T) {
+	r := &endless{}
+	dec := NewDecoder(r)
+	dec.SetOptions(Options{MaxBytes: 1 << 10})
+	var v interface{}
+	if err := dec.Decode(&v); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got: %v", err)
+	}
+	if r.read > 1<<10+1 {
+		t.Fatalf("expected at most a single byte past the limit to be read, got: %d", r.read)
+	}
+
+	deep := strings.Repeat("[", 10000) + strings.Repeat("]", 10000)
+	dec = NewDecoder(bytes.NewBufferString(deep))
+	dec.SetOptions(Options{MaxDepth: 100})
+	if err := dec.Decode(&v); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got: %v", err)
+	}
+}
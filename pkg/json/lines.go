@@ -0,0 +1,225 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LineError is returned for a line of newline-delimited JSON, which could not
+// be decoded. It wraps the error returned by the decoder.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (err *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", err.Line, err.Err)
+}
+
+// Unwrap returns the error returned when decoding the line
+func (err *LineError) Unwrap() error {
+	return err.Err
+}
+
+// LineErrors is a list of lines, which could not be decoded
+type LineErrors []*LineError
+
+func (errs LineErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// LineReader reads newline-delimited JSON (also known as NDJSON or JSON
+// Lines), decoding each line into a record of type T, as Options.Unmarshal
+// would. Blank lines are skipped, but still counted in the line numbers of
+// errors. A Decoder is not used, as it reads its input to the end, and
+// rejects anything following the first value.
+//
+//	r := json.NewLineReader[Event](f)
+//	for r.Next() {
+//		process(r.Record())
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+type LineReader[T any] struct {
+	r      *bufio.Reader
+	opts   Options
+	skip   bool
+	line   int
+	buf    []byte
+	record T
+	err    error
+	errs   LineErrors
+}
+
+// NewLineReader returns a LineReader, reading lines from the given reader
+func NewLineReader[T any](r io.Reader) *LineReader[T] {
+	return &LineReader[T]{r: bufio.NewReader(r)}
+}
+
+// SetOptions sets the options used for decoding each line. Each line is
+// decoded as a separate document, so the limits apply to every line on its
+// own, rather than to the whole input. As such, MaxBytes limits the length of
+// a line, and lines which are too long are discarded without being read into
+// memory.
+func (r *LineReader[T]) SetOptions(opts Options) {
+	r.opts = opts
+}
+
+// SetContinueOnError will enable or disable skipping lines, which cannot be
+// decoded. The errors of skipped lines are collected, and returned by Err.
+func (r *LineReader[T]) SetContinueOnError(skip bool) {
+	r.skip = skip
+}
+
+// Next will read and decode the next record, returning false when there are
+// no more records or reading has stopped because of an error
+func (r *LineReader[T]) Next() bool {
+	for r.err == nil {
+		data, long, err := r.readLine()
+		if err != nil && err != io.EOF {
+			r.err = err
+			return false
+		}
+		if err == io.EOF && len(data) == 0 && !long {
+			return false
+		}
+		r.line++
+		var record T
+		switch {
+		case long:
+			err = &LimitError{Limit: LimitBytes, Max: r.opts.MaxBytes}
+		case len(bytes.TrimSpace(data)) == 0:
+			continue
+		default:
+			err = r.opts.Unmarshal(data, &record)
+		}
+		if err != nil {
+			lineErr := &LineError{Line: r.line, Err: err}
+			if !r.skip {
+				r.err = lineErr
+				return false
+			}
+			r.errs = append(r.errs, lineErr)
+			continue
+		}
+		r.record = record
+		return true
+	}
+	return false
+}
+
+// readLine reads the next line, without allocating for every line. If the
+// line is longer than MaxBytes, the rest of the line is discarded, and long
+// is returned as true.
+func (r *LineReader[T]) readLine() (line []byte, long bool, err error) {
+	r.buf = r.buf[:0]
+	for {
+		chunk, err := r.r.ReadSlice('\n')
+		if max := r.opts.MaxBytes; max > 0 && len(r.buf)+len(bytes.TrimRight(chunk, "\r\n")) > max {
+			long = true
+		}
+		if !long {
+			r.buf = append(r.buf, chunk...)
+		}
+		if err != bufio.ErrBufferFull {
+			return r.buf, long, err
+		}
+	}
+}
+
+// Record returns the record decoded by the last call to Next
+func (r *LineReader[T]) Record() T {
+	return r.record
+}
+
+// Line returns the line number of the last line read, starting from 1
+func (r *LineReader[T]) Line() int {
+	return r.line
+}
+
+// Err returns the error which stopped reading. When continuing on errors,
+// the skipped lines are returned as LineErrors, unless reading failed.
+func (r *LineReader[T]) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.errs) > 0 {
+		return r.errs
+	}
+	return nil
+}
+
+// ErrInvalidLine is returned by LineWriter, when a value spanning several
+// lines cannot be compacted, as it is not valid JSON
+var ErrInvalidLine = errors.New("(required::json) invalid line")
+
+// LineWriter writes newline-delimited JSON, encoding each value on a single
+// line, using an Encoder. Encoded strings never contain line breaks, as they
+// are escaped, but the output of a json.Marshaler may, in which case the
+// value is validated and compacted.
+type LineWriter struct {
+	w    io.Writer
+	enc  *Encoder
+	buf  bytes.Buffer
+	line []byte
+}
+
+// NewLineWriter returns a LineWriter, writing lines to the given writer
+func NewLineWriter(w io.Writer) *LineWriter {
+	lw := &LineWriter{w: w}
+	lw.enc = NewEncoder(&lw.buf)
+	return lw
+}
+
+// SetStrict will enable or disable the strict mode of the underlying Encoder
+func (w *LineWriter) SetStrict(strict bool) {
+	w.enc.SetStrict(strict)
+}
+
+// Encode will encode the given value as a single line. Each line is written
+// with a single call to the underlying writer.
+func (w *LineWriter) Encode(v interface{}) error {
+	w.buf.Reset()
+	if err := w.enc.Encode(v); err != nil {
+		return err
+	}
+	line := w.buf.Bytes()
+	if bytes.ContainsAny(line, "\r\n") {
+		if err := validRaw(line); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidLine, err)
+		}
+		w.line = compact(w.line[:0], line)
+		line = w.line
+	}
+	_, err := w.w.Write(append(line, '\n'))
+	return err
+}
+
+// compact appends the given valid JSON to dst, without the whitespace
+// between its tokens
+func compact(dst, data []byte) []byte {
+	str, escaped := false, false
+	for _, b := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case str && b == '\\':
+			escaped = true
+		case b == '"':
+			str = !str
+		case !str && (b == ' ' || b == '\t' || b == '\n' || b == '\r'):
+			continue
+		}
+		dst = append(dst, b)
+	}
+	return dst
+}
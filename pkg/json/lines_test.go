@@ -0,0 +1,185 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type LogEntry struct {
+	Level   string `json:"level,required" validate:"oneof=debug info error"`
+	Message string `json:"msg,required"`
+	Count   int    `json:"count"`
+}
+
+func TestLineReader(t *testing.T) {
+	input := "{\"level\": \"info\", \"msg\": \"started\"}\n" +
+		"\n" +
+		"{\"level\": \"debug\", \"msg\": \"tick\", \"count\": 2}\r\n" +
+		"   \n" +
+		`{"level": "error", "msg": "stopped"}`
+	r := NewLineReader[LogEntry](strings.NewReader(input))
+	var (
+		entries []LogEntry
+		lines   []int
+	)
+	for r.Next() {
+		entries = append(entries, r.Record())
+		lines = append(lines, r.Line())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []LogEntry{{"info", "started", 0}, {"debug", "tick", 2}, {"error", "stopped", 0}}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got: %v", len(expected), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Fatalf("expected %v, got: %v", expected[i], entry)
+		}
+	}
+	if lines[0] != 1 || lines[1] != 3 || lines[2] != 5 {
+		t.Fatalf("unexpected line numbers: %v", lines)
+	}
+
+	r = NewLineReader[LogEntry](strings.NewReader(""))
+	if r.Next() || r.Err() != nil {
+		t.Fatalf("expected no records, got: %v", r.Err())
+	}
+}
+
+func TestLineReaderErrors(t *testing.T) {
+	input := strings.Join([]string{
+		`{"level": "info", "msg": "ok"}`,
+		`{"level": "info"}`,
+		`{"level": "fatal", "msg": "invalid level"}`,
+		`{"level": "info", "msg": "trailing"} {}`,
+		`{"level": "info", "msg": "ok"}`,
+		`{"level": "info", "msg": "truncated`,
+	}, "\n")
+
+	r := NewLineReader[LogEntry](strings.NewReader(input))
+	if !r.Next() || r.Next() {
+		t.Fatal("expected reading to stop at the second line")
+	}
+	var lineErr *LineError
+	if err := r.Err(); !errors.As(err, &lineErr) || lineErr.Line != 2 {
+		t.Fatalf("expected error on line 2, got: %v", err)
+	}
+	if !strings.HasPrefix(lineErr.Error(), "line 2: ") || !strings.Contains(lineErr.Error(), "msg") {
+		t.Fatalf("unexpected error: %v", lineErr)
+	}
+	if r.Next() {
+		t.Fatal("reading must not continue after an error")
+	}
+
+	r = NewLineReader[LogEntry](strings.NewReader(input))
+	r.SetContinueOnError(true)
+	var count int
+	for r.Next() {
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 records, got: %d", count)
+	}
+	var errs LineErrors
+	if err := r.Err(); !errors.As(err, &errs) {
+		t.Fatalf("expected line errors, got: %v", err)
+	}
+	var lines []int
+	for _, err := range errs {
+		lines = append(lines, err.Line)
+	}
+	if len(lines) != 4 || lines[0] != 2 || lines[1] != 3 || lines[2] != 4 || lines[3] != 6 {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+	if !strings.Contains(errs[1].Error(), "oneof") {
+		t.Fatalf("expected validation error, got: %v", errs[1])
+	}
+}
+
+func TestLineReaderOptions(t *testing.T) {
+	long := `{"level": "info", "msg": "` + strings.Repeat("a", 8192) + `"}`
+	input := strings.Join([]string{
+		`{"level": "info", "msg": "short"}`,
+		long,
+		`{"level": "info", "msg": "short"}`,
+		`{level: 'info', msg: 'lenient',}`,
+	}, "\n")
+	r := NewLineReader[LogEntry](strings.NewReader(input))
+	r.SetOptions(Options{MaxBytes: 64, Lenient: true})
+	r.SetContinueOnError(true)
+	var messages []string
+	for r.Next() {
+		messages = append(messages, r.Record().Message)
+	}
+	if strings.Join(messages, ",") != "short,short,lenient" {
+		t.Fatalf("unexpected records: %v", messages)
+	}
+	var errs LineErrors
+	if !errors.As(r.Err(), &errs) || len(errs) != 1 || errs[0].Line != 2 || !errors.Is(errs[0], ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded on line 2, got: %v", r.Err())
+	}
+}
+
+// lines is a json.Marshaler, which returns its own contents without
+// validating them
+type lines string
+
+func (l lines) MarshalJSON() ([]byte, error) {
+	return []byte(l), nil
+}
+
+func TestLineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLineWriter(&buf)
+	values := []interface{}{
+		LogEntry{"info", "multi\nline", 1},
+		RawMessage("{\n  \"a\": \"b c\",\n  \"d\": [1, 2]\n}"),
+		[]int{1, 2},
+	}
+	for _, v := range values {
+		if err := w.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := `{"level":"info","msg":"multi\nline","count":1}` + "\n" +
+		`{"a":"b c","d":[1,2]}` + "\n" +
+		`[1,2]` + "\n"
+	if buf.String() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	r := NewLineReader[LogEntry](strings.NewReader(strings.SplitN(buf.String(), "\n", 2)[0]))
+	if !r.Next() || r.Record() != values[0] {
+		t.Fatalf("expected %v, got: %v (%v)", values[0], r.Record(), r.Err())
+	}
+
+	// only the LineWriter compacts, as Marshal writes the output of a
+	// json.Marshaler as is
+	if out, err := Marshal(values[1]); err != nil || string(out) != string(values[1].(RawMessage)) {
+		t.Fatalf("%q: %v", out, err)
+	}
+
+	buf.Reset()
+	if err := w.Encode(lines("{\"a\": \"b\n")); !errors.Is(err, ErrInvalidLine) {
+		t.Fatal("expected invalid line, got:", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("nothing must be written on error, got: %q", buf.String())
+	}
+
+	type Strict struct {
+		Name string `json:"name,required"`
+	}
+	buf.Reset()
+	w.SetStrict(true)
+	if err := w.Encode(Strict{}); err == nil {
+		t.Fatal("expected strict mode error")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("nothing must be written on error, got: %q", buf.String())
+	}
+}
@@ -2,11 +2,18 @@ package json
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"sort"
 	"strconv"
+	"sync"
+
+	"github.com/Pungyeon/required/pkg/required"
+	"github.com/Pungyeon/required/pkg/structtag"
 )
 
 // Marshal is will take an object of (almost) any kind and convert this to
@@ -19,16 +26,42 @@ func Marshal(v interface{}) ([]byte, error) {
 	return marshal(v)
 }
 
+// MarshalStrict is the same as Marshal, but will first check the given value
+// using required.Validate. If any `required` field has a zero value, or any
+// value of the required package is unset or invalid, an error listing every
+// offending field is returned, and nothing is marshalled.
+func MarshalStrict(v interface{}) ([]byte, error) {
+	if err := required.Validate(v); err != nil {
+		return nil, err
+	}
+	return marshal(v)
+}
+
 // NewEncoder will return a new json Encoder, this is used for
 // marshalling a value to json directly to an io.Writer
 func NewEncoder(w io.Writer) *Encoder {
 	return &Encoder{w: w}
 }
 
+// NewStrictEncoder will return a new json Encoder, which is in strict mode
+func NewStrictEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, strict: true}
+}
+
+// SetStrict will enable or disable the strict mode of the Encoder. In strict
+// mode, values are checked as with MarshalStrict before being encoded.
+func (e *Encoder) SetStrict(strict bool) {
+	e.strict = strict
+}
+
 // Encode will take a value and encode this to json,
 // writing the eventual result to the io.Writer specified
 // in the constructor
 func (e *Encoder) Encode(v interface{}) error {
+	marshal := Marshal
+	if e.strict {
+		marshal = MarshalStrict
+	}
 	data, err := marshal(v)
 	if err != nil {
 		return err
@@ -39,7 +72,8 @@ func (e *Encoder) Encode(v interface{}) error {
 
 // Encoder is used for encoding json directory to a specified io.Writer
 type Encoder struct {
-	w io.Writer
+	w      io.Writer
+	strict bool
 }
 
 func marshal(v interface{}) ([]byte, error) {
@@ -62,37 +96,59 @@ const (
 
 var scratch [64]byte
 
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// marshaler will return the json.Marshaler implemented by the given value,
+// or by a pointer to the value, if it is addressable.
+func marshaler(val reflect.Value) (json.Marshaler, bool) {
+	if !val.IsValid() || !val.CanInterface() {
+		return nil, false
+	}
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return nil, false
+	}
+	if val.Type().Implements(marshalerType) {
+		return val.Interface().(json.Marshaler), true
+	}
+	if val.CanAddr() && val.Addr().Type().Implements(marshalerType) {
+		return val.Addr().Interface().(json.Marshaler), true
+	}
+	return nil, false
+}
+
 func _marshal(val reflect.Value, buf *bytes.Buffer) error {
+	if m, ok := marshaler(val); ok {
+		data, err := m.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		return nil
+	}
 	switch val.Kind() {
 	case reflect.Float64, reflect.Float32:
-		// The standard library uses a []byte array and AppendFloat
-		// see encode.go:573 -> func (bits floatEncoder) encode(e *encodeState, v reflect.Value, opts encOpts)
-		// I'm not exactly sure why this saves an allocation, but it does :shrug:
-		f := val.Float()
-		b := scratch[:0]
-		b = strconv.AppendFloat(b, f, 'f', -1, 64)
-		buf.Write(b)
+		if err := checkFloat(val.Float()); err != nil {
+			return err
+		}
+		writeFloat(buf, val.Float())
 		return nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		buf.WriteString(strconv.FormatInt(val.Int(), 10))
+		writeInt(buf, val.Int())
 		return nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		buf.WriteString(strconv.FormatUint(val.Uint(), 10))
+		writeUint(buf, val.Uint())
 		return nil
 	case reflect.Bool:
-		if val.Bool() {
-			buf.WriteString(TRUE)
-		} else {
-			buf.WriteString(FALSE)
-		}
+		writeBool(buf, val.Bool())
 		return nil
 	case reflect.String:
-		buf.WriteRune(quote)
-		buf.WriteString(val.String())
-		buf.WriteRune(quote)
+		writeString(buf, val.String())
 		return nil
 	case reflect.Struct:
 		return marshalStruct(val, buf)
+	case reflect.Invalid:
+		buf.WriteString("null")
+		return nil
 
 	case reflect.Ptr:
 		if val.IsNil() {
@@ -105,6 +161,9 @@ func _marshal(val reflect.Value, buf *bytes.Buffer) error {
 			buf.WriteString("null")
 			return nil
 		}
+		if vs, ok := variantsOf(val.Type()); ok {
+			return marshalVariant(val.Elem(), vs, buf)
+		}
 		return _marshal(val.Elem(), buf)
 	case reflect.Map:
 		if val.IsNil() {
@@ -113,7 +172,7 @@ func _marshal(val reflect.Value, buf *bytes.Buffer) error {
 		}
 		return marshalMap(val, buf)
 	case reflect.Array, reflect.Slice:
-		if val.IsNil() {
+		if val.Kind() == reflect.Slice && val.IsNil() {
 			buf.WriteString("null")
 			return nil
 		}
@@ -123,8 +182,88 @@ func _marshal(val reflect.Value, buf *bytes.Buffer) error {
 	return errUnsupportedType{val: val}
 }
 
+func writeFloat(buf *bytes.Buffer, f float64) {
+	// The standard library uses a []byte array and AppendFloat
+	// see encode.go:573 -> func (bits floatEncoder) encode(e *encodeState, v reflect.Value, opts encOpts)
+	// I'm not exactly sure why this saves an allocation, but it does :shrug:
+	b := scratch[:0]
+	b = strconv.AppendFloat(b, f, 'f', -1, 64)
+	buf.Write(b)
+}
+
+func writeInt(buf *bytes.Buffer, n int64) {
+	buf.WriteString(strconv.FormatInt(n, 10))
+}
+
+func writeUint(buf *bytes.Buffer, n uint64) {
+	buf.WriteString(strconv.FormatUint(n, 10))
+}
+
+func writeBool(buf *bytes.Buffer, b bool) {
+	if b {
+		buf.WriteString(TRUE)
+	} else {
+		buf.WriteString(FALSE)
+	}
+}
+
+// writeString will write the given string as a JSON string, escaping quotes,
+// backslashes and control characters, as required by RFC 8259. Every string
+// written by this package, including object keys, is written by this function.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteRune(quote)
+	start := 0
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b >= 0x20 && b != '"' && b != '\\' {
+			continue
+		}
+		buf.WriteString(s[start:i])
+		switch b {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(b)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hex[b>>4])
+			buf.WriteByte(hex[b&0xf])
+		}
+		start = i + 1
+	}
+	buf.WriteString(s[start:])
+	buf.WriteRune(quote)
+}
+
+const hex = "0123456789abcdef"
+
+// quoted returns the given string as a JSON string
+func quoted(s string) string {
+	var buf bytes.Buffer
+	writeString(&buf, s)
+	return buf.String()
+}
+
 var ErrUnsupportedType = errors.New("(required::json) unsupported type")
 
+// ErrUnsupportedValue is returned when marshalling a value, which cannot be
+// represented as JSON, such as infinite floating point numbers
+var ErrUnsupportedValue = errors.New("(required::json) unsupported value")
+
+// checkFloat returns an error if the given float cannot be represented as
+// JSON
+func checkFloat(f float64) error {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return fmt.Errorf("%w: %v", ErrUnsupportedValue, f)
+	}
+	return nil
+}
+
 type errUnsupportedType struct {
 	val reflect.Value
 }
@@ -156,24 +295,32 @@ func marshalArray(val reflect.Value, buf *bytes.Buffer) error {
 	return nil
 }
 
+// marshalMap will write the given map as a JSON object. As with the std
+// library, the keys are sorted, to ensure that the output is deterministic.
 func marshalMap(val reflect.Value, buf *bytes.Buffer) error {
 	buf.WriteString("{")
+	keys := make([]string, 0, val.Len())
+	values := make(map[string]reflect.Value, val.Len())
 	kv := val.MapRange()
-
-	hasNext := kv.Next()
-	for hasNext {
-		if err := marshalMapField(kv.Key(), buf); err != nil {
+	for kv.Next() {
+		var key bytes.Buffer
+		if err := marshalMapField(kv.Key(), &key); err != nil {
 			return err
 		}
-		buf.WriteRune(colon)
+		keys = append(keys, key.String())
+		values[key.String()] = kv.Value()
+	}
+	sort.Strings(keys)
 
-		if err := _marshal(kv.Value(), buf); err != nil {
-			return err
-		}
-		hasNext = kv.Next()
-		if hasNext {
+	for i, key := range keys {
+		if i > 0 {
 			buf.WriteByte(',')
 		}
+		buf.WriteString(key)
+		buf.WriteRune(colon)
+		if err := _marshal(values[key], buf); err != nil {
+			return err
+		}
 	}
 
 	buf.WriteString("}")
@@ -204,9 +351,7 @@ func marshalMapField(val reflect.Value, buf *bytes.Buffer) error {
 		buf.WriteRune(quote)
 		return nil
 	case reflect.String:
-		buf.WriteRune(quote)
-		buf.WriteString(val.String())
-		buf.WriteRune(quote)
+		writeString(buf, val.String())
 		return nil
 	}
 	return fmt.Errorf("unsupported map key: %v %v", val.Kind(), val.Type())
@@ -218,35 +363,35 @@ func marshalStruct(val reflect.Value, buf *bytes.Buffer) error {
 	if err != nil {
 		return err
 	}
-	var i int
-	for tags[i].private {
-		i++
-	}
-	for i < val.NumField() {
+	var written bool
+	for i := 0; i < val.NumField(); i++ {
+		if tags[i].private {
+			continue
+		}
+		if written {
+			buf.WriteByte(',')
+		}
 		buf.WriteString(tags[i].name)
 		buf.WriteRune(colon)
 		if err := _marshal(val.Field(i), buf); err != nil {
 			return err
 		}
-		i++
-		for i < val.NumField() && tags[i].private {
-			i++
-		}
-		if i < val.NumField()-1 {
-			buf.WriteByte(',')
-		}
+		written = true
 	}
 	buf.WriteString("}")
 	return nil
 }
 
-var fieldCache = make(map[reflect.Type][]field)
+// fieldCache holds the fields of every struct type marshalled so far, as a
+// reflect.Type to []field map
+var fieldCache sync.Map
 
 type field struct {
 	private     bool
 	name        string
 	required    bool
 	omitifempty bool
+	notnull     bool
 }
 
 var diff uint8 = 'a' - 'A'
@@ -272,36 +417,24 @@ func addCreatedTag(tags []field, i int, f reflect.StructField) {
 }
 
 func addParsedTag(tags []field, i int, f reflect.StructField, jsonTag string) error {
-	var s, c = 0, 0
-	for c < len(jsonTag) {
-		if jsonTag[c] == ',' {
-			if tags[i].name == "" {
-				tags[i].name = `"` + jsonTag[s:c] + `"`
-			} else {
-				switch jsonTag[s:c] {
-				case "required":
-					tags[i].required = true
-				case "omitifempty":
-					tags[i].omitifempty = true
-				default:
-					return fmt.Errorf("illegal json tag: %v", jsonTag)
-				}
-			}
-			s = c
-		}
-		c++
+	tag, err := structtag.Parse(jsonTag, i)
+	if err != nil {
+		return fmt.Errorf("illegal json tag: %v: %w", jsonTag, err)
 	}
+	tags[i].name = quoted(tag.FieldName)
+	tags[i].required = tag.Required
+	tags[i].omitifempty = tag.OmitIfEmpty
+	tags[i].notnull = tag.NotNull
 	tags[i].private = f.PkgPath != ""
 	return nil
 }
 
 func getJSONTags(val reflect.Value) ([]field, error) {
 	var f reflect.StructField
-	tags, ok := fieldCache[val.Type()]
-	if ok {
-		return tags, nil
+	if tags, ok := fieldCache.Load(val.Type()); ok {
+		return tags.([]field), nil
 	}
-	tags = make([]field, val.NumField())
+	tags := make([]field, val.NumField())
 	for i := 0; i < val.NumField(); i++ {
 		f = val.Type().Field(i)
 		jsonTag, ok := f.Tag.Lookup("json")
@@ -313,6 +446,6 @@ func getJSONTags(val reflect.Value) ([]field, error) {
 			}
 		}
 	}
-	fieldCache[val.Type()] = tags
+	fieldCache.Store(val.Type(), tags)
 	return tags, nil
 }
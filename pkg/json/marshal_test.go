@@ -4,7 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"math"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/Pungyeon/required/pkg/required"
+	"github.com/Pungyeon/required/pkg/validate"
 )
 
 var (
@@ -116,6 +122,27 @@ func TestEncoder(t *testing.T) {
 	}
 }
 
+type Celsius float64
+
+func (c Celsius) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatFloat(float64(c), 'f', 1, 64) + `C"`), nil
+}
+
+func TestMarshalMarshaler(t *testing.T) {
+	type Reading struct {
+		Room        string `json:"room"`
+		Temperature Celsius
+		Unset       *Celsius
+	}
+	data, err := marshal(Reading{Room: "kitchen", Temperature: 21.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"room":"kitchen","temperature":"21.5C","unset":null}` {
+		t.Fatal(string(data))
+	}
+}
+
 func BenchmarkMarshalStd(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		data, err := json.Marshal(obj)
@@ -135,3 +162,107 @@ func BenchmarkMarshalPkg(b *testing.B) {
 		_ = data
 	}
 }
+
+func TestMarshalTagOptions(t *testing.T) {
+	type Order struct {
+		Method  string `json:"method,default=card"`
+		Address string `json:"address,required_if=method:card"`
+		IDs     []int  `json:"ids,default=[1,2]"`
+	}
+	data, err := marshal(Order{Method: "cash", IDs: []int{3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"method":"cash","address":"","ids":[3]}` {
+		t.Fatal(string(data))
+	}
+}
+
+func TestMarshalStrict(t *testing.T) {
+	type Line struct {
+		SKU      string       `json:"sku,required"`
+		Quantity required.Int `json:"quantity"`
+	}
+	type Invoice struct {
+		ID    string `json:"id,required"`
+		Note  string `json:"note"`
+		Lines []Line `json:"lines"`
+	}
+
+	valid := Invoice{ID: "1", Lines: []Line{{SKU: "a", Quantity: required.NewInt(1)}}}
+	data, err := MarshalStrict(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"id":"1","note":"","lines":[{"sku":"a","quantity":1}]}` {
+		t.Fatal(string(data))
+	}
+
+	missing := Invoice{Lines: []Line{{Quantity: required.NewInt(1)}}}
+	if _, err := Marshal(missing); err != nil {
+		t.Fatal("Marshal must not be strict:", err)
+	}
+
+	invalid := Invoice{Lines: []Line{{SKU: "a", Quantity: required.NewInt(1)}, {}}}
+	_, err = MarshalStrict(invalid)
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatal("expected errors:", err)
+	}
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.Path)
+	}
+	if strings.Join(paths, " ") != "id lines[1].sku lines[1].quantity" {
+		t.Fatal("unexpected errors:", err)
+	}
+	if !errors.Is(err, required.ErrEmptyInt) {
+		t.Fatal("expected empty int error:", err)
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	if err := enc.Encode(missing); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	enc.SetStrict(true)
+	if err := enc.Encode(missing); err == nil || buf.Len() != 0 {
+		t.Fatal("expected strict encoder to fail without writing:", err, buf.String())
+	}
+	if err := NewStrictEncoder(&buf).Encode(&valid); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMarshalEscapes(t *testing.T) {
+	values := []string{"plain", `quote " and backslash \`, "lines\n\r\t", "control \x00\x01\x1f", "unicode π  "}
+	for _, s := range values {
+		data, err := Marshal(map[string]string{s: s})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v map[string]string
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatalf("%q: invalid output: %s: %v", s, data, err)
+		}
+		if v[s] != s {
+			t.Fatalf("expected: %q, got: %q", s, v[s])
+		}
+		var decoded map[string]string
+		if err := Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded[s] != s {
+			t.Fatalf("expected: %q, got: %q", s, decoded[s])
+		}
+	}
+}
+
+func TestMarshalUnsupportedValue(t *testing.T) {
+	for _, f := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		if _, err := Marshal(f); !errors.Is(err, ErrUnsupportedValue) {
+			t.Fatalf("%v: expected unsupported value, got: %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package json
+
+import (
+	"github.com/Pungyeon/required/pkg/lexer"
+)
+
+// Options configure how JSON is decoded. The zero value decodes JSON in the
+// same way as Unmarshal.
+type Options struct {
+	// OrderedObjects will decode objects, which are decoded without type
+	// information, such as into an interface{}, as an *OrderedMap rather
+	// than a map[string]interface{}, so the order of the keys is preserved.
+	OrderedObjects bool
+	// SliceCapacity is the initial capacity of slices decoded from arrays.
+	// If the length of arrays is known up front, setting the capacity
+	// avoids growing the slices while decoding.
+	SliceCapacity int
+	// DisallowDuplicateKeys will reject objects with duplicate keys anywhere
+	// in the document, including within skipped values. Otherwise, the last
+	// of any duplicate keys is used. This should be set for security
+	// sensitive input, such as signatures and claims, which may otherwise
+	// be interpreted differently by different decoders.
+	DisallowDuplicateKeys bool
+	// Lenient will decode documents in the lenient mode of the lexer, which
+	// accepts comments, trailing commas, single-quoted strings, unquoted
+	// keys, hexadecimal numbers, Infinity and NaN, as described by
+	// lexer.SetLenient. This is intended for hand-edited documents, such as
+	// configuration files, which are validated exactly like JSON documents.
+	// Skipped values, such as the values decoded by a json.Unmarshaler or
+	// into a RawMessage, are converted into JSON.
+	Lenient bool
+
+	// The following limits protect against untrusted input, exhausting
+	// memory or the stack. A limit of zero means no limit. When a limit is
+	// exceeded, a *LimitError is returned, which wraps ErrLimitExceeded.
+	//
+	// MaxBytes is the maximum size of the document. The Decoder never reads
+	// more than a single byte past this limit.
+	MaxBytes int
+	// MaxDepth is the maximum nesting depth of objects and arrays
+	MaxDepth int
+	// MaxStringLength is the maximum length in bytes of any string,
+	// including object keys
+	MaxStringLength int
+	// MaxArrayLength is the maximum number of elements of any array
+	MaxArrayLength int
+	// MaxObjectMembers is the maximum number of members of any object
+	MaxObjectMembers int
+}
+
+// Unmarshal is the same as the Unmarshal function, but decodes the data
+// using the options
+func (opts Options) Unmarshal(data []byte, v interface{}) error {
+	if err := opts.checkBytes(data); err != nil {
+		return err
+	}
+	return parse(lexer.NewLexer(data), v, opts)
+}
+
+// SetOptions will set the options used by the Decoder
+func (d *Decoder) SetOptions(opts Options) {
+	d.opts = opts
+}
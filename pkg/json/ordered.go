@@ -0,0 +1,145 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// OrderedMap is a JSON object, which preserves the order of its keys. It is
+// produced by decoding objects without type information, using the
+// OrderedObjects option, and is encoded with its keys in the same order, so
+// documents such as configuration files can be round-tripped.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty OrderedMap
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: map[string]interface{}{}}
+}
+
+// Get returns the value of the given key
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set will set the value of the given key. A new key is added after all
+// other keys, while an existing key keeps its position.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = map[string]interface{}{}
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Delete will remove the given key
+func (m *OrderedMap) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Keys returns the keys in order
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// Len returns the number of keys
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// MarshalJSON writes the object with its keys in order
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(&buf, key)
+		buf.WriteRune(colon)
+		data, err := marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an object, preserving the order of its keys. Nested
+// objects are decoded as an *OrderedMap as well.
+func (m *OrderedMap) UnmarshalJSON(data []byte) error {
+	p := &parser{lexer: lexer.NewLexer(data), opts: Options{OrderedObjects: true}}
+	if err := p.next(); err != nil {
+		return err
+	}
+	if p.current.Type == token.Null {
+		return nil
+	}
+	if p.current.Type != token.OpenCurly {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object, got: %s", p.current))
+	}
+	obj, err := p.orderedMap()
+	if err != nil {
+		return err
+	}
+	*m = *obj
+	return nil
+}
+
+// orderedMap will decode the object starting at the current token as an
+// *OrderedMap. Unless duplicate keys are disallowed, the last of any
+// duplicate keys is used, but the key keeps the position of its first
+// occurrence.
+func (p *parser) orderedMap() (*OrderedMap, error) {
+	obj := NewOrderedMap()
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var seen keys
+	for p.current.Type != token.ClosingCurly {
+		if err := p.checkMembers(obj.Len() + 1); err != nil {
+			return nil, err
+		}
+		field, err := p.member()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.unique(&seen, field.Value); err != nil {
+			return nil, err
+		}
+		key := field.ToString()
+		p.push(segment{field: key})
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		p.pop()
+		obj.Set(key, v)
+		if err := p.separator(token.ClosingCurly); err != nil {
+			return nil, err
+		}
+	}
+	return obj, checkIfEOF(p.next())
+}
@@ -0,0 +1,172 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var config = []byte(`{"name":"service","port":8080,"zeta":{"b":1,"a":[{"y":true,"x":null}]},"alpha":2.5}`)
+
+func TestOrderedObjects(t *testing.T) {
+	var v interface{}
+	if err := (Options{OrderedObjects: true}).Unmarshal(config, &v); err != nil {
+		t.Fatal(err)
+	}
+	obj, ok := v.(*OrderedMap)
+	if !ok {
+		t.Fatalf("expected *OrderedMap, got: %T", v)
+	}
+	if !reflect.DeepEqual(obj.Keys(), []string{"name", "port", "zeta", "alpha"}) {
+		t.Fatal(obj.Keys())
+	}
+	zeta, _ := obj.Get("zeta")
+	if keys := zeta.(*OrderedMap).Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Fatal(keys)
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, config) {
+		t.Fatalf("\nexpected: %s\ngot:      %s", config, data)
+	}
+
+	// without the option, objects are decoded as maps
+	if err := Unmarshal(config, &v); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		t.Fatalf("expected map, got: %T", v)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap
+	if err := Unmarshal([]byte(`{"b": 1, "a": 2, "b": 3}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Keys(), []string{"b", "a"}) {
+		t.Fatal(m.Keys())
+	}
+	if v, _ := m.Get("b"); v != 3 {
+		t.Fatal(v)
+	}
+	m.Set("c", []interface{}{"x"})
+	m.Set("a", nil)
+	m.Delete("b")
+	m.Delete("unknown")
+	data, err := Marshal(&m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"a":null,"c":["x"]}` || m.Len() != 2 {
+		t.Fatal(string(data))
+	}
+
+	var s struct {
+		Config *OrderedMap `json:"config"`
+	}
+	if err := Unmarshal([]byte(`{"config": {"z": {"y": 1, "x": 2}}}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	z, _ := s.Config.Get("z")
+	if keys := z.(*OrderedMap).Keys(); !reflect.DeepEqual(keys, []string{"y", "x"}) {
+		t.Fatal(keys)
+	}
+	if err := Unmarshal([]byte(`[1]`), &m); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSliceCapacity(t *testing.T) {
+	var v struct {
+		Typed   []int         `json:"typed"`
+		Untyped []interface{} `json:"untyped"`
+	}
+	opts := Options{SliceCapacity: 64}
+	if err := opts.Unmarshal([]byte(`{"typed": [1, 2, 3, 4], "untyped": [1, "a"]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v.Typed, []int{1, 2, 3, 4}) || cap(v.Typed) != 64 {
+		t.Fatal(v.Typed, cap(v.Typed))
+	}
+	if len(v.Untyped) != 2 || cap(v.Untyped) != 64 {
+		t.Fatal(v.Untyped, cap(v.Untyped))
+	}
+
+	dec := NewDecoder(bytes.NewReader([]byte(`{"a": [1]}`)))
+	dec.SetOptions(Options{OrderedObjects: true})
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tree.(*OrderedMap); !ok {
+		t.Fatalf("expected *OrderedMap, got: %T", tree)
+	}
+}
+
+func TestTree(t *testing.T) {
+	tree, err := ParseTree(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt := []struct {
+		path  string
+		value interface{}
+	}{
+		{"name", "service"},
+		{"port", 8080},
+		{"zeta.b", 1},
+		{"zeta.a[0].y", true},
+		{"zeta.a[0].x", nil},
+		{"alpha", 2.5},
+	}
+	for _, tc := range tt {
+		v, err := tree.Get(tc.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != tc.value {
+			t.Fatalf("%s: expected %v, got %v", tc.path, tc.value, v)
+		}
+	}
+	if root, err := tree.Get(""); err != nil || root != tree.Root() {
+		t.Fatal(root, err)
+	}
+
+	arr, err := ParseTree([]byte(`[[1, {"a": "b"}]]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := arr.Get("[0][1].a"); err != nil || v != "b" {
+		t.Fatal(v, err)
+	}
+
+	for _, path := range []string{"missing", "zeta.a[1]", "zeta.b.c", "name[0]", "zeta.a[0].z"} {
+		if _, err := tree.Get(path); !errors.Is(err, ErrPathNotFound) {
+			t.Fatalf("%s: expected not found, got: %v", path, err)
+		}
+	}
+	for _, path := range []string{".name", "name.", "zeta..b", "zeta.a[x]", "zeta.a[0", "zeta.[0]", "zeta.a[-1]"} {
+		if _, err := tree.Get(path); !errors.Is(err, ErrInvalidPath) {
+			t.Fatalf("%s: expected invalid path, got: %v", path, err)
+		}
+	}
+
+	data, err := Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, config) {
+		t.Fatalf("\nexpected: %s\ngot:      %s", config, data)
+	}
+	if _, err := ParseTree([]byte(`{} {}`)); err == nil {
+		t.Fatal("expected error for trailing data")
+	}
+	if _, err := ParseTree(nil); err == nil {
+		t.Fatal("expected error for empty document")
+	}
+}
@@ -5,58 +5,99 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strconv"
 
 	"github.com/Pungyeon/required/pkg/lexer"
 	"github.com/Pungyeon/required/pkg/required"
 	"github.com/Pungyeon/required/pkg/structtag"
 	"github.com/Pungyeon/required/pkg/token"
+	"github.com/Pungyeon/required/pkg/validate"
 )
 
 func Parse(l *lexer.Lexer, v interface{}) error {
+	return parse(l, v, Options{})
+}
+
+func parse(l *lexer.Lexer, v interface{}, opts Options) error {
 	val := getReflectValue(v)
-	if val.Kind() == reflect.Interface {
-		p := &parser{lexer: l}
-		obj, err := p.parse(val)
-		if err != nil {
-			return err
-		}
-		val.Set(obj)
-		return nil
+	p := &parser{lexer: l, opts: opts}
+	p.path = p.segments[:0]
+	if opts.Lenient {
+		l.SetLenient(true)
 	}
-	p := &parser{lexer: l}
 	if err := p.next(); err != nil {
+		if err == io.EOF {
+			return token.Error(token.ErrInvalidJSON, "empty document")
+		}
 		return err
 	}
 	if err := p.decode(val); err != nil {
 		return err
 	}
+	if p.current.Type != token.Unknown {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected data after document: %s", p.current))
+	}
+	if len(p.errs) > 0 {
+		return p.errs
+	}
 	return nil
 }
 
+// decode will decode the value starting at the current token into the given
+// reflect.Value. Once decoded, the current token of the parser will be the
+// token immediately following the decoded value.
 func (p *parser) decode(val reflect.Value) error {
 	tags, err := structtag.FromValue(val)
 	if err != nil {
 		return err
 	}
-	if tags.UnmarshalInterface {
+	isNull := p.current.Type == token.Null
+	if tags.DecoderInterface && val.CanAddr() {
+		// the contained value is decoded directly, rather than by UnmarshalJSON,
+		// so that its own tags and rules are applied
+		target := val.Addr().Interface().(required.Decoder).DecodeValue(isNull)
+		if target == nil {
+			err = checkIfEOF(p.next())
+		} else {
+			err = p.decode(reflect.ValueOf(target).Elem())
+		}
+	} else if tags.UnmarshalInterface {
+		var data []byte
+		if data, err = p.skipJSON(); err != nil {
+			return err
+		}
 		if val.CanAddr() {
-			str := p.lexer.SkipValue()
-			return val.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(str)
+			err = val.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data)
 		} else {
-			return val.Interface().(json.Unmarshaler).UnmarshalJSON(p.lexer.SkipValue())
+			err = val.Interface().(json.Unmarshaler).UnmarshalJSON(data)
 		}
+		err = p.prefix(err)
+	} else {
+		err = p._decode(val, tags)
 	}
-
-	if err := p._decode(val, tags); err != nil {
+	if err != nil {
 		return err
 	}
 
 	if tags.RequiredInterface {
 		if val.CanAddr() {
-			return val.Addr().Interface().(required.Required).IsValueValid()
+			err = val.Addr().Interface().(required.Required).IsValueValid()
+		} else {
+			err = val.Interface().(required.Required).IsValueValid()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// pointers are validated once their element has been decoded
+	if tags.ValidatorInterface && !isNull && val.Kind() != reflect.Ptr {
+		var v validate.Validator
+		if val.CanAddr() {
+			v = val.Addr().Interface().(validate.Validator)
 		} else {
-			return val.Interface().(required.Required).IsValueValid()
+			v = val.Interface().(validate.Validator)
 		}
+		p.invalid(validate.NewContext(p.Path()).Run(v))
 	}
 	return nil
 }
@@ -69,23 +110,35 @@ func checkIfEOF(err error) error {
 }
 
 func (p *parser) _decode(val reflect.Value, tags structtag.Tags) error {
-	if val.Kind() == reflect.Interface {
-		obj, err := p.parse(val)
-		if err != nil {
-			return err
-		}
-		val.Set(obj)
-		return nil
-	}
-
 	if p.current.Type == token.Null {
-		return nil
+		switch val.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			val.Set(reflect.Zero(val.Type()))
+		}
+		return checkIfEOF(p.next())
 	}
 
 	switch val.Kind() {
+	case reflect.Interface:
+		if vs, ok := variantsOf(val.Type()); ok {
+			return p.decodeVariant(val, vs)
+		}
+		if val.NumMethod() != 0 {
+			return fmt.Errorf("cannot decode into non-empty interface: %v", val.Type())
+		}
+		v, err := p.value()
+		if err != nil {
+			return err
+		}
+		if v == nil {
+			val.Set(reflect.Zero(val.Type()))
+		} else {
+			val.Set(reflect.ValueOf(v))
+		}
+		return nil
 	case reflect.Ptr:
-		vo := getValueOfPointer(val)
-		if err := p._decode(getElemOfValue(vo), tags); err != nil {
+		vo := reflect.New(val.Type().Elem())
+		if err := p.decode(vo.Elem()); err != nil {
 			return err
 		}
 		val.Set(vo)
@@ -96,86 +149,189 @@ func (p *parser) _decode(val reflect.Value, tags structtag.Tags) error {
 		}
 		return checkIfEOF(p.next())
 	case reflect.Map:
-		return p.decodeMap(val)
-	case reflect.Struct:
-		if err := p.decodeObject(val, tags); err != nil {
+		if err := p.decodeMap(val); err != nil {
 			return err
 		}
 		return checkIfEOF(p.next())
-	case reflect.Int, reflect.Float32, reflect.Float64,
-		reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.String, reflect.Bool: // what about uints?
-		if err := p.current.SetValueOf(val); err != nil {
+	case reflect.Struct:
+		if err := p.decodeObject(val, tags); err != nil {
 			return err
 		}
 		return checkIfEOF(p.next())
+	case reflect.String:
+		return decodeString(p, val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeInt(p, val)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		n, err := token.Ttoi(p.current)
-		if err != nil {
-			return err
-		}
-		val.SetUint(uint64(n))
-		return err
+		return decodeUint(p, val)
+	case reflect.Float32, reflect.Float64:
+		return decodeFloat(p, val)
+	case reflect.Bool:
+		return decodeBool(p, val)
 	}
 	return token.Error(token.ErrInvalidJSON, p.current.ToString())
 }
 
-func (p *parser) decodeObject(val reflect.Value, tags structtag.Tags) error {
+// readString will read the current token as a string
+func (p *parser) readString() (string, error) {
+	if err := p.checkString(); err != nil {
+		return "", err
+	}
+	s := p.current.ToString()
+	return s, checkIfEOF(p.next())
+}
+
+// readInt will read the current token as a signed integer
+func (p *parser) readInt() (int64, error) {
+	n, err := token.Ttoi(p.current)
+	if err != nil {
+		return 0, err
+	}
+	return n, checkIfEOF(p.next())
+}
+
+// readUint will read the current token as an unsigned integer
+func (p *parser) readUint() (uint64, error) {
+	n, err := strconv.ParseUint(p.current.ToString(), 10, 64)
+	if err != nil {
+		return 0, token.Error(token.ErrInvalidValue, fmt.Sprintf("%v: %v", p.current, err))
+	}
+	return n, checkIfEOF(p.next())
+}
+
+// readFloat will read the current token as a floating point number
+func (p *parser) readFloat() (float64, error) {
+	f, err := token.Ttof(p.current)
+	if err != nil {
+		return 0, err
+	}
+	return f, checkIfEOF(p.next())
+}
+
+// readBool will read the current token as a boolean
+func (p *parser) readBool() (bool, error) {
+	if p.current.Type != token.Boolean {
+		return false, token.Error(token.ErrInvalidValue, p.current.String())
+	}
+	b := p.current.ToString() == "true"
+	return b, checkIfEOF(p.next())
+}
+
+// member will read the name of an object member and the following colon,
+// leaving the parser at the first token of the member value.
+func (p *parser) member() (token.Token, error) {
+	if p.current.Type != token.String {
+		return p.current, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object field, got: %s", p.current))
+	}
+	field := p.current
+	if err := p.checkString(); err != nil {
+		return field, err
+	}
 	if err := p.next(); err != nil {
-		return checkIfEOF(err)
+		return field, err
 	}
-	for {
-		if p.current.Type != token.String {
-			if p.current.Type == token.ClosingCurly {
-				return tags.CheckRequired()
-			}
-			return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object field, got: %s", p.current))
-		}
-		field := p.current
-		if err := p.next(); err != nil {
-			return checkIfEOF(err)
-		}
-		if p.current.Type != token.Colon {
-			return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected colon token: %s", p.current))
-		}
+	if p.current.Type != token.Colon {
+		return field, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected colon token: %s", p.current))
+	}
+	return field, p.next()
+}
+
+// separator will advance past the comma following an object member or
+// array element. If the closing token is found instead, the parser is left
+// at the closing token.
+func (p *parser) separator(closing token.TokenType) error {
+	switch p.current.Type {
+	case token.Comma:
 		if err := p.next(); err != nil {
-			return checkIfEOF(err)
-		}
-		tag, ok := tags.Tags[field.ToString()]
-		if !ok {
-			p.lexer.SkipValue()
-			if err := p.next(); err != nil {
-				return checkIfEOF(err)
-			}
-			continue
+			return err
 		}
-		if err := p.decode(val.Field(tag.FieldIndex)); err != nil {
-			return checkIfEOF(err)
+		if p.current.Type == closing {
+			return token.Error(token.ErrInvalidJSON, "trailing comma")
 		}
+		return nil
+	case closing:
+		return nil
+	}
+	return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected %s or comma: (%s) -> %s", closing, p.current, p.lexer.Previous()))
+}
 
-		if p.lexer.EOF() {
-			return nil
+func (p *parser) decodeObject(val reflect.Value, tags structtag.Tags) error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+	if err := p.next(); err != nil {
+		return err
+	}
+	pl := planOf(val.Type(), tags)
+	state := tags.NewState()
+	var (
+		seen keys
+		n    int
+	)
+	for p.current.Type != token.ClosingCurly {
+		n++
+		if err := p.checkMembers(n); err != nil {
+			return err
 		}
-
-		if p.current.Type == token.Null {
-			if err := p.next(); err != nil {
-				return checkIfEOF(err)
-			}
-		} else {
-			tags.Set(tag)
+		field, err := p.member()
+		if err != nil {
+			return err
+		}
+		if err := p.unique(&seen, field.Value); err != nil {
+			return err
 		}
-		if p.current.Type == token.Comma {
-			if err := p.next(); err != nil {
-				return checkIfEOF(err)
+		f, ok := pl.lookup(field.Value)
+		if !ok || !val.Field(f.tag.FieldIndex).CanSet() {
+			if err := p.skipMember(field); err != nil {
+				return err
 			}
 		} else {
-			if p.current.Type == token.ClosingCurly {
-				return tags.CheckRequired()
+			isNull := p.current.Type == token.Null
+			if isNull {
+				state.Set(f.tag, structtag.Null)
 			} else {
-				return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected closing curly or comma: (%s) -> %s", p.current, p.lexer.Previous()))
+				state.Set(f.tag, structtag.Present)
 			}
+			p.push(segment{field: f.key})
+			if err := f.decode(p, val.Field(f.tag.FieldIndex)); err != nil {
+				return err
+			}
+			if !isNull && !f.tag.Rules.IsEmpty() {
+				p.invalid(f.tag.Rules.Validate(val.Field(f.tag.FieldIndex)).Prefix(p.Path()))
+			}
+			p.pop()
+		}
+		if err := p.separator(token.ClosingCurly); err != nil {
+			return err
 		}
 	}
+	for _, key := range tags.Defaults {
+		tag := tags.Tags[key]
+		if state[tag.FieldIndex] != structtag.Absent || !val.Field(tag.FieldIndex).CanSet() {
+			continue
+		}
+		p.push(segment{field: key})
+		if err := p.decodeRaw(val.Field(tag.FieldIndex), tag.Default); err != nil {
+			return err
+		}
+		p.pop()
+	}
+	return tags.CheckRequired(val, state)
+}
+
+// decodeRaw will decode the given raw value, such as the default value of an
+// absent field, with the same path as the value currently being decoded
+func (p *parser) decodeRaw(val reflect.Value, data []byte) error {
+	d := &parser{lexer: lexer.NewLexer(data), path: p.path, opts: p.opts, depth: p.depth}
+	if err := d.next(); err != nil {
+		return err
+	}
+	if err := d.decode(val); err != nil {
+		return err
+	}
+	p.invalid(d.errs)
+	return nil
 }
 
 func grow(arr reflect.Value, i int) reflect.Value {
@@ -188,322 +344,308 @@ func grow(arr reflect.Value, i int) reflect.Value {
 }
 
 func (p *parser) decodeArray(arr reflect.Value) error {
-	arr.Set(reflect.MakeSlice(arr.Type(), 3, 3))
-	tags, err := structtag.FromValue(arr.Index(0))
-	if err != nil {
+	if p.current.Type != token.OpenBrace {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected array, got: %s", p.current))
+	}
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+	if arr.Kind() == reflect.Slice {
+		n := 3
+		if p.opts.SliceCapacity > 0 {
+			n = p.opts.SliceCapacity
+		}
+		arr.Set(reflect.MakeSlice(arr.Type(), n, n))
+	}
+	if err := p.next(); err != nil {
 		return err
 	}
 
 	var i int
-	for {
-		if err := p.next(); err != nil {
+	for p.current.Type != token.ClosingBrace {
+		if err := p.checkArray(i); err != nil {
 			return err
 		}
-		switch p.current.Type {
-		case token.Comma:
-			continue // skip commas
-		case token.ClosingBrace:
-			arr.Set(arr.Slice(0, i))
-			return nil
-		case token.OpenCurly:
-			arr.Set(grow(arr, i))
-			if arr.Index(i).Kind() == reflect.Map {
-				if err := p.decodeMap(arr.Index(i)); err != nil {
-					return err
-				}
-			} else {
-				if err := p.decodeObject(arr.Index(i), tags); err != nil {
-					return err
-				}
+		if arr.Kind() == reflect.Array && i >= arr.Len() {
+			if _, err := p.skip(); err != nil {
+				return err
 			}
-			i++
-			if p.current.Type == token.ClosingBrace {
-				arr.Set(arr.Slice(0, i))
-				return nil
+		} else {
+			if arr.Kind() == reflect.Slice {
+				arr.Set(grow(arr, i))
 			}
-		case token.OpenBrace:
-			arr.Set(grow(arr, i))
-			if err := p.decodeArray(arr.Index(i)); err != nil {
+			p.push(segment{index: i, array: true})
+			if err := p.decode(arr.Index(i)); err != nil {
 				return err
 			}
-			i++
-		default:
-			arr.Set(grow(arr, i))
-			// Doing this check saves ~12 allocations per op
-			if arr.Type().Elem().Kind() == reflect.Interface {
-				val, err := p.current.AsValue(arr.Type().Elem())
-				if err != nil {
-					return err
-				}
-				arr.Index(i).Set(val)
-			} else {
-				if err := p.current.SetValueOf(arr.Index(i)); err != nil {
-					return err
-				}
-			}
-			i++
+			p.pop()
 		}
-	}
-}
-
-type parser struct {
-	lexer    *lexer.Lexer
-	obj      reflect.Value
-	current  token.Token
-	previous token.Token
-}
-
-func (p *parser) eof() bool {
-	return p.lexer.EOF()
-}
-
-func (p *parser) next() error {
-	var err error
-	p.previous = p.current
-	p.current, err = p.lexer.Next()
-	return err
-}
-
-func (p *parser) parse(vo reflect.Value) (reflect.Value, error) {
-	for {
-		if err := p.next(); err != nil {
-			return vo, checkIfEOF(err)
-		}
-		switch p.current.Type {
-		case token.OpenBrace:
-			return p.parseArray(determineArrayType(vo))
-		case token.OpenCurly:
-			return p.parseObject(vo)
-		case token.Null:
-			return vo, nil
-		default:
-			if vo.Kind() == reflect.Interface {
-				return p.current.AsValue(vo.Type())
-			}
-			return vo, p.current.SetValueOf(vo)
+		i++
+		if err := p.separator(token.ClosingBrace); err != nil {
+			return err
 		}
 	}
-}
-
-func (p *parser) parseObject(vo reflect.Value) (reflect.Value, error) {
-	kind, _type := determineObjectType(vo)
-	if kind == reflect.Map {
-		return p.parseMap(_type)
+	if arr.Kind() == reflect.Slice {
+		arr.Set(arr.Slice(0, i))
 	}
-	return vo, p.parseStructure(vo)
+	return nil
 }
 
-func determineObjectType(vo reflect.Value) (reflect.Kind, reflect.Type) {
-	if vo.Kind() == reflect.Interface {
-		return reflect.Map, token.ReflectTypeInterface
-	} else if vo.Kind() == reflect.Map {
-		return reflect.Map, vo.Type().Elem()
-	} else {
-		return vo.Kind(), vo.Type()
+func (p *parser) decodeMap(vmap reflect.Value) error {
+	if p.current.Type != token.OpenCurly {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object, got: %s", p.current))
 	}
-}
-
-func determineArrayType(vo reflect.Value) reflect.Type {
-	//if vo.Kind() != reflect.Slice {
-	//	return reflect.ValueOf([]interface{}{}).Type()
-	//}
-	if vo.Kind() == reflect.Interface {
-		return reflect.ValueOf([]interface{}{}).Type()
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+	if vmap.IsNil() {
+		vmap.Set(reflect.MakeMap(vmap.Type()))
+	}
+	if err := p.next(); err != nil {
+		return err
+	}
+	var seen keys
+	for n := 1; p.current.Type != token.ClosingCurly; n++ {
+		if err := p.checkMembers(n); err != nil {
+			return err
+		}
+		field, err := p.member()
+		if err != nil {
+			return err
+		}
+		if err := p.unique(&seen, field.Value); err != nil {
+			return err
+		}
+		key, err := mapKey(vmap.Type().Key(), field)
+		if err != nil {
+			return err
+		}
+		val := reflect.New(vmap.Type().Elem()).Elem()
+		p.push(segment{field: field.ToString()})
+		if err := p.decode(val); err != nil {
+			return err
+		}
+		p.pop()
+		vmap.SetMapIndex(key, val)
+		if err := p.separator(token.ClosingCurly); err != nil {
+			return err
+		}
 	}
-	return vo.Type()
+	return nil
 }
 
-func insertAt(arr reflect.Value, i int, val reflect.Value) reflect.Value {
-	if i < arr.Len() {
-		arr.Index(i).Set(val)
-		return arr
+// mapKey will convert the given object field into a value of the given map
+// key type. Only string and integer keys are supported, mirroring Marshal.
+func mapKey(keyType reflect.Type, field token.Token) (reflect.Value, error) {
+	key := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		key.SetString(field.ToString())
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := token.Ttoi(field)
+		if err != nil {
+			return key, err
+		}
+		key.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(field.ToString(), 10, 64)
+		if err != nil {
+			return key, token.Error(token.ErrInvalidValue, fmt.Sprintf("%v: %v", field, err))
+		}
+		key.SetUint(n)
+		return key, nil
 	}
-	return reflect.Append(arr, val)
+	return key, fmt.Errorf("unsupported map key: %v", keyType)
 }
 
-func (p *parser) parseArray(sliceType reflect.Type) (reflect.Value, error) {
-	var (
-		arr = reflect.MakeSlice(sliceType, 3, 3)
-		val = reflect.New(sliceType.Elem()).Elem()
-		i   int
-	)
-	for {
+// value will decode the value starting at the current token, without any
+// type information. Objects are decoded as map[string]interface{}, or as
+// *OrderedMap if the OrderedObjects option is set, arrays as []interface{}
+// and null as nil.
+func (p *parser) value() (interface{}, error) {
+	switch p.current.Type {
+	case token.OpenCurly:
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		if p.opts.OrderedObjects {
+			return p.orderedMap()
+		}
+		obj := make(map[string]interface{})
 		if err := p.next(); err != nil {
-			return arr.Slice(0, i), checkIfEOF(err)
-		}
-		switch p.current.Type {
-		case token.Comma:
-			continue // skip commas
-		case token.ClosingBrace:
-			return arr.Slice(0, i), nil
-		case token.OpenCurly:
-			inner, err := p.parseMap(val.Type())
+			return nil, err
+		}
+		var seen keys
+		for p.current.Type != token.ClosingCurly {
+			if err := p.checkMembers(len(obj) + 1); err != nil {
+				return nil, err
+			}
+			field, err := p.member()
 			if err != nil {
-				return arr, err
+				return nil, err
 			}
-			arr = insertAt(arr, i, inner)
-			i++
-			if p.current.Type == token.ClosingBrace {
-				return arr.Slice(0, i), nil
+			if err := p.unique(&seen, field.Value); err != nil {
+				return nil, err
 			}
-		case token.OpenBrace:
-			inner, err := p.parseArray(sliceType.Elem())
+			key := field.ToString()
+			p.push(segment{field: key})
+			v, err := p.value()
 			if err != nil {
-				return inner, err
+				return nil, err
 			}
-			arr = insertAt(arr, i, inner)
-			i++
-		default:
-			// Doing this check saves ~12 allocations per op
-			if sliceType.Elem().Kind() == reflect.Interface {
-				var err error
-				val, err = p.current.AsValue(sliceType.Elem())
-				if err != nil {
-					return val, nil
-				}
-			} else {
-				if err := p.current.SetValueOf(val); err != nil {
-					return val, nil
-				}
+			p.pop()
+			obj[key] = v
+			if err := p.separator(token.ClosingCurly); err != nil {
+				return nil, err
+			}
+		}
+		return obj, checkIfEOF(p.next())
+	case token.OpenBrace:
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		arr := make([]interface{}, 0, p.opts.SliceCapacity)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		for p.current.Type != token.ClosingBrace {
+			if err := p.checkArray(len(arr)); err != nil {
+				return nil, err
+			}
+			p.push(segment{index: len(arr), array: true})
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			p.pop()
+			arr = append(arr, v)
+			if err := p.separator(token.ClosingBrace); err != nil {
+				return nil, err
 			}
-			arr = insertAt(arr, i, val)
-			i++
+		}
+		return arr, checkIfEOF(p.next())
+	case token.Null:
+		return nil, checkIfEOF(p.next())
+	case token.String:
+		if err := p.checkString(); err != nil {
+			return nil, err
 		}
 	}
+	val, err := p.current.ToValue()
+	if err != nil {
+		return nil, err
+	}
+	return val.Interface(), checkIfEOF(p.next())
 }
 
-func getValueOfPointer(vo reflect.Value) reflect.Value {
-	a := reflect.New(vo.Type())
-	b := a.Elem()
-	a.Elem().Set(reflect.New(b.Type().Elem()))
-	return reflect.ValueOf(a.Elem().Interface())
-}
-
-func (p *parser) parsePointerObject(vo reflect.Value) error {
-	ptr := getValueOfPointer(vo)
-	if err := p.parseStructure(getElemOfValue(ptr)); err != nil {
+// prefix will prefix the paths of the errors returned by a json.Unmarshaler
+// with the path of the decoded value, as the Unmarshaler only sees the raw
+// value, and reports paths relative to it
+func (p *parser) prefix(err error) error {
+	if len(p.path) == 0 {
 		return err
 	}
-	vo.Set(ptr)
-	return nil
+	switch err := err.(type) {
+	case validate.Errors:
+		return err.Prefix(p.Path())
+	case variantErr:
+		err.path = validate.Join(p.Path(), err.path)
+		return err
+	}
+	return err
 }
 
-func (p *parser) parseStructure(vo reflect.Value) error {
-	tags, err := structtag.FromValue(vo)
+// skip will advance the parser past the value starting at the current
+// token, returning the exact raw bytes of the skipped value.
+func (p *parser) skip() ([]byte, error) {
+	data, err := p.lexer.SkipCurrent()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// Ignore the field, as it's not defined on the given structure
-	if len(tags.Tags) == 0 {
-		return nil
+	if err := p.checkSkipped(data); err != nil {
+		return nil, err
 	}
-	if vo.Kind() == reflect.Ptr {
-		return p.parsePointerObject(vo)
-	}
-	for {
-		if err := p.next(); err != nil {
-			return checkIfEOF(err)
-		}
+	return data, checkIfEOF(p.next())
+}
 
-		if p.current.Type == token.Colon {
-			tag := tags.Tags[p.previous.ToString()]
-			obj := vo.Field(tag.FieldIndex)
-			if !obj.CanSet() { // Private values may not be set.
-				continue
-			}
-			val, err := p.parse(obj)
-			if err != nil {
-				return err
-			}
-			tags.Set(tag) // TODO : Make sure to not set this, if the token is a NullToken
-			obj.Set(val)
-		}
-		if p.eof() || p.current.Type == token.ClosingCurly {
-			if err := p.next(); err != nil {
-				return checkIfEOF(err)
-			}
-			return tags.CheckRequired()
-		}
-	}
+type parser struct {
+	lexer    *lexer.Lexer
+	current  token.Token
+	previous token.Token
+	path     []segment
+	errs     validate.Errors
+	opts     Options
+	// depth is the number of objects and arrays currently being decoded
+	depth int
+	// segments is the initial storage of the path, so that decoding
+	// shallow documents does not allocate a path
+	segments [8]segment
 }
 
-func getReflectValue(v interface{}) reflect.Value {
-	return getElemOfValue(reflect.ValueOf(v))
+// segment is a single element of the JSON path of the value being decoded,
+// which is either an object field or, if array is set, an array index. The
+// path is only formatted as a string, once an error has occurred.
+type segment struct {
+	field string
+	index int
+	array bool
 }
 
-func getElemOfValue(vo reflect.Value) reflect.Value {
-	for vo.Kind() == reflect.Ptr {
-		vo = vo.Elem()
-	}
-	return vo
+func (p *parser) push(s segment) {
+	p.path = append(p.path, s)
 }
-func (p *parser) decodeMap(vmap reflect.Value) error {
-	var (
-		val   = reflect.New(vmap.Type().Elem()).Elem()
-		field = reflect.New(token.ReflectTypeString).Elem()
-		err   error
-	)
 
-	if vmap.IsNil() {
-		vmap.Set(reflect.MakeMap(reflect.MapOf(token.ReflectTypeString, vmap.Type().Elem())))
-	}
+func (p *parser) pop() {
+	p.path = p.path[:len(p.path)-1]
+}
 
-	for {
-		if err := p.next(); err != nil {
-			return checkIfEOF(err)
-		}
-		if p.current.Type == token.ClosingCurly {
-			return checkIfEOF(p.next())
-		}
-		err = p.setField(field)
-		if err != nil {
-			return err
-		}
-		val, err = p.parse(val)
-		if err != nil {
-			return err
+// Path returns the JSON path of the value currently being decoded
+func (p *parser) Path() string {
+	return joinPath(p.path)
+}
+
+func joinPath(segments []segment) string {
+	var path string
+	for _, s := range segments {
+		switch {
+		case s.array:
+			path = validate.Join(path, validate.Index(s.index))
+		case s.field == "":
+			// the empty key would otherwise disappear from the path
+			path = validate.Join(path, `[""]`)
+		default:
+			path = validate.Join(path, s.field)
 		}
-		vmap.SetMapIndex(field, val)
 	}
+	return path
 }
 
-func (p *parser) parseMap(valueType reflect.Type) (reflect.Value, error) {
-	var (
-		val   = reflect.New(valueType).Elem()
-		field = reflect.New(token.ReflectTypeString).Elem()
-		err   error
-	)
+// invalid will add the given validation errors to the errors, which are
+// returned once decoding has finished
+func (p *parser) invalid(errs validate.Errors) {
+	p.errs = append(p.errs, errs...)
+}
 
-	vmap := reflect.MakeMap(reflect.MapOf(token.ReflectTypeString, valueType))
+func (p *parser) next() error {
+	var err error
+	p.previous = p.current
+	p.current, err = p.lexer.Next()
+	return err
+}
 
-	for {
-		if err := p.next(); err != nil {
-			return vmap, checkIfEOF(err)
-		}
-		if p.current.Type == token.ClosingCurly {
-			if err := p.next(); err != nil {
-				return vmap, checkIfEOF(err)
-			}
-		}
-		err = p.setField(field)
-		if err != nil {
-			return vmap, err
-		}
-		val, err = p.parse(val)
-		if err != nil {
-			return vmap, err
-		}
-		vmap.SetMapIndex(field, val)
-	}
+func getReflectValue(v interface{}) reflect.Value {
+	return getElemOfValue(reflect.ValueOf(v))
 }
 
-func (p *parser) setField(val reflect.Value) error {
-	for {
-		if err := p.next(); err != nil {
-			return checkIfEOF(err)
-		}
-		if p.current.Type == token.Colon {
-			return p.previous.SetValueOf(val)
-		}
+func getElemOfValue(vo reflect.Value) reflect.Value {
+	for vo.Kind() == reflect.Ptr {
+		vo = vo.Elem()
 	}
+	return vo
 }
@@ -5,14 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Pungyeon/required/pkg/token"
 
 	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/required"
 	"github.com/Pungyeon/required/pkg/structtag"
+	"github.com/Pungyeon/required/pkg/validate"
 )
 
 type TestObject struct {
@@ -443,16 +448,20 @@ func (email CustomRequiredEmail) IsValueValid() error {
 }
 
 func TestRequiredFields(t *testing.T) {
-	type RequiredBoi struct {
-		Name string `json:"name, required"`
-	}
-
-	var r RequiredBoi
-	if err := Parse(LexString(t, `{}`), &r); !structtag.IsRequiredErr(err) {
+	// the tag options may be separated by spaces. The type is built with
+	// reflection, as go vet reports spaces in struct tag literals.
+	requiredBoi := reflect.StructOf([]reflect.StructField{{
+		Name: "Name",
+		Type: reflect.TypeOf(""),
+		Tag:  `json:"name, required"`,
+	}})
+
+	r := reflect.New(requiredBoi).Interface()
+	if err := Parse(LexString(t, `{}`), r); !structtag.IsRequiredErr(err) {
 		t.Fatal("no required error, or unexpected error returned:", err)
 	}
 
-	if err := Parse(LexString(t, `{"name": "lasse"}`), &r); err != nil {
+	if err := Parse(LexString(t, `{"name": "lasse"}`), r); err != nil {
 		t.Fatal(err)
 	}
 	type TestUser struct {
@@ -470,6 +479,41 @@ func TestRequiredFields(t *testing.T) {
 	}
 }
 
+// cachedEmail is only used by TestValidateThenUnmarshal, so that its tags
+// are first cached by required.Validate
+type cachedEmail struct {
+	Address string
+}
+
+func (email *cachedEmail) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &email.Address); err != nil {
+		return err
+	}
+	return CustomRequiredEmail(email.Address).IsValueValid()
+}
+
+func TestValidateThenUnmarshal(t *testing.T) {
+	// the email is reached through an interface, so it is not settable.
+	// The tags cached for its type must still report the Unmarshaler
+	// interface, which is used when decoding.
+	type Holder struct {
+		X interface{}
+	}
+	type User struct {
+		Email cachedEmail `json:"e"`
+	}
+	if err := required.Validate(&Holder{X: cachedEmail{}}); err != nil {
+		t.Fatal(err)
+	}
+	var user User
+	if err := Unmarshal([]byte(`{"e": ""}`), &user); err != errEmailRequired {
+		t.Fatal("expected email required error, got:", err)
+	}
+	if err := Unmarshal([]byte(`{"e": "lasse@jakobsen.dev"}`), &user); err != nil || user.Email.Address != "lasse@jakobsen.dev" {
+		t.Fatal(user, err)
+	}
+}
+
 func TestNullSupport(t *testing.T) {
 	var d Ding
 	if err := Parse(LexString(t, `{"object": null}`), &d); err != nil {
@@ -489,6 +533,7 @@ func TestNullSupport(t *testing.T) {
 }
 
 func BenchmarkStdUnmarshal(b *testing.B) {
+	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		var ding Ding
 		if err := json.Unmarshal([]byte(sample), &ding); err != nil {
@@ -498,6 +543,7 @@ func BenchmarkStdUnmarshal(b *testing.B) {
 }
 
 func BenchmarkPkgUnmarshal(b *testing.B) {
+	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
 		var ding Ding
 		if err := Unmarshal([]byte(sample), &ding); err != nil {
@@ -668,3 +714,561 @@ func TestNonExistingField(t *testing.T) {
 type C struct {
 	Data map[string]interface{} `json:"data"`
 }
+
+func TestRequiredStateIsPerDecode(t *testing.T) {
+	type RequiredBoi struct {
+		Name string `json:"name,required"`
+	}
+
+	var r RequiredBoi
+	if err := Parse(LexString(t, `{"name": "lasse"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if err := Parse(LexString(t, `{}`), &r); !structtag.IsRequiredErr(err) {
+		t.Fatal("no required error, or unexpected error returned:", err)
+	}
+}
+
+func TestUnmarshalerField(t *testing.T) {
+	type Wrapper struct {
+		Before string    `json:"before"`
+		Value  IntString `json:"value"`
+		After  uint      `json:"after"`
+	}
+
+	var w Wrapper
+	if err := Parse(LexString(t, `{"before": "a", "value": {"value": "123"}, "after": 2}`), &w); err != nil {
+		t.Fatal(err)
+	}
+	if w.Before != "a" || w.Value.Value != 123 || w.After != 2 {
+		t.Fatal(w)
+	}
+}
+
+func TestNestedInterface(t *testing.T) {
+	var v interface{}
+	if err := Parse(LexString(t, `{"a": [1, {"b": null}, -2.5], "c": {}, "d": "e"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	defer recovery(t, v)
+
+	obj := v.(map[string]interface{})
+	arr := obj["a"].([]interface{})
+	if arr[0].(int) != 1 || arr[1].(map[string]interface{})["b"] != nil || arr[2].(float64) != -2.5 ||
+		len(obj["c"].(map[string]interface{})) != 0 || obj["d"].(string) != "e" {
+		t.Fatal(v)
+	}
+}
+
+func TestSkipUnknownFields(t *testing.T) {
+	var obj TestObject
+	if err := Parse(LexString(t, `{"unknown": {"a": [1, "}"]}, "name": "lasse", "last": [[]]}`), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj.Name != "lasse" {
+		t.Fatal(obj)
+	}
+
+	invalid := []string{
+		`{"unknown": {"a": "}"], "name": "lasse"}`,
+		`{"unknown": "abc, "name": "lasse"}`,
+		`{"unknown": tru, "name": "lasse"}`,
+	}
+	for _, data := range invalid {
+		if err := Unmarshal([]byte(data), &obj); err == nil {
+			t.Fatal("expected error:", data)
+		}
+	}
+}
+
+type rawBytes []byte
+
+func (r *rawBytes) UnmarshalJSON(data []byte) error {
+	*r = append((*r)[:0], data...)
+	return nil
+}
+
+func TestUnmarshalerRawBytes(t *testing.T) {
+	values := []string{`"a\"}"`, `{"a": ["]", {"b": null}]}`, `-1.5e3`, `true`, `[]`}
+	for _, value := range values {
+		var v struct {
+			Raw   rawBytes `json:"raw"`
+			After string   `json:"after"`
+		}
+		if err := Unmarshal([]byte(`{"raw": `+value+`, "after": "x"}`), &v); err != nil {
+			t.Fatal(err)
+		}
+		if string(v.Raw) != value || v.After != "x" {
+			t.Fatalf("expected %s, got %s", value, v.Raw)
+		}
+	}
+}
+
+func TestNotNullFields(t *testing.T) {
+	type Settings struct {
+		Theme string                    `json:"theme,notnull"`
+		Name  required.Optional[string] `json:"name,notnull"`
+		Page  required.Optional[int]    `json:"page"`
+	}
+
+	tt := []struct {
+		name string
+		json string
+		err  bool
+	}{
+		{"absent", `{}`, false},
+		{"present", `{"theme": "dark", "name": "lasse"}`, false},
+		{"null string", `{"theme": null}`, true},
+		{"null optional", `{"name": null}`, true},
+		{"null nullable optional", `{"page": null}`, false},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var s Settings
+			err := Parse(LexString(t, tf.json), &s)
+			if tf.err != structtag.IsRequiredErr(err) {
+				t.Fatal("unexpected error:", err)
+			}
+			if !tf.err && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestOptionalFields(t *testing.T) {
+	type Patch struct {
+		Name  required.Optional[string]              `json:"name"`
+		Email required.Optional[CustomRequiredEmail] `json:"email"`
+		Age   required.Optional[int]                 `json:"age"`
+	}
+
+	var p Patch
+	if err := Parse(LexString(t, `{"name": null, "age": 31}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Name.IsNull() || !p.Email.IsAbsent() || p.Age.Value() != 31 {
+		t.Fatalf("%+v", p)
+	}
+
+	if err := Parse(LexString(t, `{"email": "dingeling.dk"}`), &p); err != errEmailRequired {
+		t.Fatal("no required error, or unexpected error returned:", err)
+	}
+
+	// the contents are decoded with their own tags
+	type Address struct {
+		Street  string `json:"street_name,required"`
+		Country string `json:"country" validate:"len=2"`
+	}
+	var shipping required.Optional[Address]
+	if err := Parse(LexString(t, `{"street_name": "vesterbrogade"}`), &shipping); err != nil || shipping.Value().Street != "vesterbrogade" {
+		t.Fatalf("%+v: %v", shipping, err)
+	}
+	if err := Parse(LexString(t, `{"country": "DK"}`), &shipping); !structtag.IsRequiredErr(err) {
+		t.Fatal("expected required error, got:", err)
+	}
+	var errs validate.Errors
+	if err := Parse(LexString(t, `{"street_name": "a", "country": "DNK"}`), &shipping); !errors.As(err, &errs) || errs[0].Path != "country" {
+		t.Fatal("expected validation error, got:", err)
+	}
+}
+
+func TestGenericContents(t *testing.T) {
+	type Address struct {
+		Street  string `json:"street_name,required"`
+		Country string `json:"country" validate:"len=2"`
+	}
+	type Order struct {
+		Billing  required.Value[Address]    `json:"billing"`
+		Shipping required.Optional[Address] `json:"shipping"`
+		Items    required.Slice[Address]    `json:"items"`
+	}
+
+	var o Order
+	if err := Parse(LexString(t, `{
+		"billing": {"street_name": "vesterbrogade", "country": "DK"},
+		"shipping": null,
+		"items": [{"street_name": "nørrebrogade"}]
+	}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Billing.Value().Street != "vesterbrogade" || !o.Shipping.IsNull() || o.Items.Value()[0].Street != "nørrebrogade" {
+		t.Fatalf("%+v", o)
+	}
+
+	for _, input := range []string{
+		`{"billing": {"country": "DK"}, "items": [{"street_name": "a"}]}`,
+		`{"billing": {"street_name": "a"}, "shipping": {}, "items": [{"street_name": "a"}]}`,
+		`{"billing": {"street_name": "a"}, "items": [{"street_name": "a"}, {}]}`,
+	} {
+		if err := Parse(LexString(t, input), &Order{}); !structtag.IsRequiredErr(err) {
+			t.Fatalf("%s: expected required error, got: %v", input, err)
+		}
+	}
+
+	err := Parse(LexString(t, `{"billing": {"street_name": "a", "country": "DNK"}, "items": [{"street_name": "a", "country": "D"}]}`), &Order{})
+	var errs validate.Errors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Path != "billing.country" || errs[1].Path != "items[0].country" {
+		t.Fatalf("unexpected errors: %v", err)
+	}
+
+	if err := Parse(LexString(t, `{"billing": {"street_name": "a"}, "items": []}`), &Order{}); !errors.Is(err, required.ErrEmptySlice[Address]()) {
+		t.Fatalf("expected empty slice error, got: %v", err)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	type Address struct {
+		Country string `json:"country" validate:"len=2"`
+	}
+	type User struct {
+		Name      string    `json:"name" validate:"min=1,max=16"`
+		Email     string    `json:"email" validate:"email"`
+		Role      string    `json:"role" validate:"oneof=admin user"`
+		Age       int       `json:"age" validate:"gte=0,lt=150"`
+		Tags      []string  `json:"tags" validate:"max=3,dive,min=1"`
+		Addresses []Address `json:"addresses"`
+	}
+
+	var user User
+	if err := Parse(LexString(t, `{
+		"name": "lasse",
+		"email": "lasse@jakobsen.dev",
+		"role": "admin",
+		"age": 31,
+		"tags": ["golang"],
+		"addresses": [{"country": "DK"}]
+	}`), &user); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Parse(LexString(t, `{
+		"name": "",
+		"email": "lasse.jakobsen.dev",
+		"role": "admin",
+		"age": -1,
+		"tags": ["golang", ""],
+		"addresses": [{"country": "DK"}, {"country": "DNK"}]
+	}`), &user)
+	var errs validate.Errors
+	if !errors.As(err, &errs) || !errors.Is(err, validate.ErrInvalid) {
+		t.Fatal("expected validation errors:", err)
+	}
+	paths := make([]string, len(errs))
+	for i, err := range errs {
+		paths[i] = err.Path
+	}
+	expected := []string{"name", "email", "age", "tags[1]", "addresses[1].country"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatal("unexpected paths:", paths)
+	}
+}
+
+func TestEmptyKeyPath(t *testing.T) {
+	type Address struct {
+		Country string `json:"country" validate:"len=2"`
+	}
+	var v struct {
+		ByKey map[string][]Address `json:"by_key"`
+	}
+	err := Unmarshal([]byte(`{"by_key": {"": [{"country": "DNK"}]}}`), &v)
+	var errs validate.Errors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatal("expected a validation error:", err)
+	}
+	if errs[0].Path != `by_key[""][0].country` {
+		t.Fatal("unexpected path:", errs[0].Path)
+	}
+}
+
+func TestValidateIllegalTag(t *testing.T) {
+	var v struct {
+		Age int `json:"age" validate:"email"`
+	}
+	if err := Parse(LexString(t, `{"age": 1}`), &v); !errors.Is(err, validate.ErrIllegalRule) {
+		t.Fatal("expected illegal rule error:", err)
+	}
+}
+
+func TestConditionalRequiredFields(t *testing.T) {
+	type Payment struct {
+		Method         string `json:"method,required"`
+		BillingAddress string `json:"billing_address,required_if=method:card"`
+		Email          string `json:"email,oneof=contact"`
+		Phone          string `json:"phone,oneof=contact"`
+	}
+
+	tt := []struct {
+		name string
+		json string
+		err  bool
+	}{
+		{"card", `{"method": "card", "billing_address": "Dingvej 1", "email": "ding@dong.dk"}`, false},
+		{"card without address", `{"method": "card", "email": "ding@dong.dk"}`, true},
+		{"card with null address", `{"method": "card", "billing_address": null, "phone": "12345678"}`, true},
+		{"cash without address", `{"method": "cash", "phone": "12345678"}`, false},
+		{"no contact", `{"method": "cash"}`, true},
+		{"both contacts", `{"method": "cash", "email": "ding@dong.dk", "phone": "12345678"}`, true},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var p Payment
+			err := Parse(LexString(t, tf.json), &p)
+			if tf.err != structtag.IsRequiredErr(err) {
+				t.Fatal("unexpected error:", err)
+			}
+			if !tf.err && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+type Booking struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+func (b *Booking) Validate(ctx *validate.Context) error {
+	if b.End < b.Start {
+		ctx.AddError("end", errors.New("must not be before start"))
+	}
+	return nil
+}
+
+type Schedule struct {
+	Owner    string    `json:"owner"`
+	Bookings []Booking `json:"bookings"`
+	Next     *Booking  `json:"next"`
+}
+
+func (s Schedule) Validate(ctx *validate.Context) error {
+	if len(s.Bookings) == 0 {
+		return errors.New("must have at least one booking")
+	}
+	return nil
+}
+
+func TestValidatorInterface(t *testing.T) {
+	var s Schedule
+	err := Parse(LexString(t, `{
+		"owner": "lasse",
+		"bookings": [{"start": 1, "end": 2}, {"start": 3, "end": 2}],
+		"next": {"start": 5, "end": 4}
+	}`), &s)
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatal("expected validation errors:", err)
+	}
+	if err.Error() != "bookings[1].end: must not be before start; next.end: must not be before start" {
+		t.Fatal("unexpected errors:", err)
+	}
+
+	var empty struct {
+		Schedule Schedule `json:"schedule"`
+	}
+	err = Parse(LexString(t, `{"schedule": {"owner": "lasse", "bookings": [], "next": null}}`), &empty)
+	if err == nil || err.Error() != "schedule: must have at least one booking" {
+		t.Fatal("unexpected errors:", err)
+	}
+
+	if err := Parse(LexString(t, `{"owner": "lasse", "bookings": [{"start": 1, "end": 2}]}`), &s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultValues(t *testing.T) {
+	type Query struct {
+		PageSize int                    `json:"page_size,default=50" validate:"max=100"`
+		Sort     string                 `json:"sort,default=name"`
+		Desc     *bool                  `json:"desc,default=true"`
+		Timeout  time.Duration          `json:"timeout,default=5s"`
+		Interval required.Duration      `json:"interval,default=1m"`
+		IDs      []int                  `json:"ids,default=[1,2,3]"`
+		Labels   map[string]string      `json:"labels,default={\"env\":\"dev\"}"`
+		Page     required.Optional[int] `json:"page,default=1"`
+	}
+
+	var q Query
+	if err := Parse(LexString(t, `{"sort": "date", "ids": null}`), &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.PageSize != 50 || q.Sort != "date" || q.Desc == nil || !*q.Desc ||
+		q.Timeout != 5*time.Second || q.Interval.Value() != time.Minute || q.IDs != nil ||
+		q.Labels["env"] != "dev" || q.Page.Value() != 1 {
+		t.Fatalf("%+v", q)
+	}
+
+	// defaults must never be shared between decoded values
+	var other Query
+	if err := Parse(LexString(t, `{}`), &other); err != nil {
+		t.Fatal(err)
+	}
+	other.IDs[0] = 42
+	other.Labels["env"] = "prod"
+	if err := Parse(LexString(t, `{}`), &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.IDs[0] != 1 || q.Labels["env"] != "dev" {
+		t.Fatalf("%+v", q)
+	}
+
+	var bad struct {
+		PageSize int `json:"page_size,required,default=50"`
+	}
+	if err := Parse(LexString(t, `{}`), &bad); err == nil {
+		t.Fatal("expected error for required field with default value")
+	}
+}
+
+func TestDecoderPlan(t *testing.T) {
+	type Shadowed struct {
+		First string `json:"name"`
+		Name  string
+		Age   int
+		Score float64
+	}
+	tags, err := structtag.FromValue(reflect.ValueOf(&Shadowed{}).Elem())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := planOf(reflect.TypeOf(Shadowed{}), tags)
+	if !reflect.DeepEqual(pl.names, []string{"age", "name", "score"}) {
+		t.Fatal(pl.names)
+	}
+	if f, ok := pl.lookup([]byte("name")); !ok || f.tag.FieldIndex != 1 {
+		t.Fatal(f, ok)
+	}
+	if _, ok := pl.lookup([]byte("unknown")); ok {
+		t.Fatal("unknown field found")
+	}
+
+	var v Shadowed
+	if err := Unmarshal([]byte(`{"name": "lasse", "age": 32, "score": null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v != (Shadowed{Name: "lasse", Age: 32}) {
+		t.Fatal(v)
+	}
+}
+
+func TestConcurrentUnmarshal(t *testing.T) {
+	// every type is new to the caches, so the first decodes of each type
+	// race to compile and store its tags and plan. Run with -race.
+	type Address struct {
+		Street string `json:"street,required"`
+		Zip    int    `json:"zip" validate:"min=1000"`
+	}
+	type Customer struct {
+		Name      string             `json:"name,required"`
+		Addresses []Address          `json:"addresses"`
+		Tags      map[string]Address `json:"tags"`
+	}
+	type Order struct {
+		ID       int      `json:"id,required"`
+		Customer Customer `json:"customer"`
+	}
+	data := []byte(`{"id": 1, "customer": {"name": "lasse", "addresses": [{"street": "a", "zip": 2100}], "tags": {"home": {"street": "b", "zip": 8000}}}}`)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var order Order
+			if err := Unmarshal(data, &order); err != nil {
+				t.Error(err)
+				return
+			}
+			if order.Customer.Tags["home"].Zip != 8000 {
+				t.Errorf("unexpected order: %v", order)
+			}
+			if _, err := Marshal(order); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// The unmarshal benchmarks compare the decoder with encoding/json, for each
+// of the test payloads. With precompiled decoder plans, the results were:
+//
+//	BenchmarkUnmarshal/sample/std   147540     7553 ns/op    576 B/op   18 allocs/op
+//	BenchmarkUnmarshal/sample/pkg   108124     9791 ns/op   1456 B/op   41 allocs/op
+//	BenchmarkUnmarshal/object/std  3753136    498.5 ns/op     16 B/op    1 allocs/op
+//	BenchmarkUnmarshal/object/pkg  2154794    543.0 ns/op    264 B/op    7 allocs/op
+//	BenchmarkUnmarshal/custom/std  1716891     1095 ns/op     24 B/op    2 allocs/op
+//	BenchmarkUnmarshal/custom/pkg   888998     1499 ns/op    248 B/op    6 allocs/op
+func BenchmarkUnmarshal(b *testing.B) {
+	payloads := []struct {
+		name string
+		data []byte
+		new  func() interface{}
+	}{
+		{"sample", []byte(sample), func() interface{} { return &Ding{} }},
+		{"object", []byte(`{"name": "lasse"}`), func() interface{} { return &TestObject{} }},
+		{"custom", dateSample, func() interface{} { return &IntString{} }},
+	}
+	for _, payload := range payloads {
+		b.Run(payload.name+"/std", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := json.Unmarshal(payload.data, payload.new()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(payload.name+"/pkg", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := Unmarshal(payload.data, payload.new()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+type Flat struct {
+	Name   string  `json:"name,required"`
+	Age    int     `json:"age"`
+	Score  float64 `json:"score"`
+	Active bool    `json:"active"`
+}
+
+type FlatNumbers struct {
+	ID    uint64 `json:"id,required"`
+	Count int    `json:"count"`
+	Valid bool   `json:"valid"`
+}
+
+// TestUnmarshalAllocations enforces the allocation budget of decoding flat
+// structs: one allocation for the lexer, one for the parser, and one for
+// every string value.
+func TestUnmarshalAllocations(t *testing.T) {
+	tt := []struct {
+		name   string
+		data   string
+		v      interface{}
+		budget float64
+	}{
+		{"flat", `{"name": "lasse", "age": 32, "score": 1.5, "active": true, "unknown": [1, {"a": "b"}]}`, &Flat{}, 3},
+		{"numbers", `{"id": 1, "count": -20, "valid": false, "unknown": null}`, &FlatNumbers{}, 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			data := []byte(tc.data)
+			allocs := testing.AllocsPerRun(100, func() {
+				if err := Unmarshal(data, tc.v); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > tc.budget {
+				t.Fatalf("%v allocations, budget is %v", allocs, tc.budget)
+			}
+		})
+	}
+}
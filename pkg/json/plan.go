@@ -0,0 +1,164 @@
+package json
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/Pungyeon/required/pkg/structtag"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// decoderFunc decodes the value starting at the current token of the parser
+// into the given reflect.Value
+type decoderFunc func(p *parser, val reflect.Value) error
+
+// plan is the precompiled decoder of a struct type. The fields are sorted by
+// their JSON name, so that a field is found using a binary search, and each
+// field has a decoder chosen by the kind of the field, once.
+type plan struct {
+	names  []string
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	key    string
+	tag    structtag.Tag
+	decode decoderFunc
+}
+
+// planCache holds the plan of every struct type seen so far, as a
+// reflect.Type to *plan map
+var planCache sync.Map
+
+// planOf returns the plan of the given struct type, compiling it the first
+// time the type is seen
+func planOf(t reflect.Type, tags structtag.Tags) *plan {
+	if pl, ok := planCache.Load(t); ok {
+		return pl.(*plan)
+	}
+	pl := &plan{}
+	for _, key := range tags.Keys() {
+		tag := tags.Tags[key]
+		pl.fields = append(pl.fields, fieldPlan{
+			key:    key,
+			tag:    tag,
+			decode: decoderOf(t.Field(tag.FieldIndex).Type),
+		})
+	}
+	// when several fields share a name, the last field is used, as with
+	// the Tags
+	sort.SliceStable(pl.fields, func(i, j int) bool {
+		return pl.fields[i].key < pl.fields[j].key
+	})
+	fields := pl.fields[:0]
+	for i, f := range pl.fields {
+		if i+1 < len(pl.fields) && pl.fields[i+1].key == f.key {
+			continue
+		}
+		fields = append(fields, f)
+		pl.names = append(pl.names, f.key)
+	}
+	pl.fields = fields
+	// if the type has been compiled concurrently, the first plan is kept
+	actual, _ := planCache.LoadOrStore(t, pl)
+	return actual.(*plan)
+}
+
+// lookup returns the field with the given JSON name. The name is compared
+// without being converted to a string, so no allocation is made.
+func (pl *plan) lookup(name []byte) (*fieldPlan, bool) {
+	i, j := 0, len(pl.names)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if pl.names[h] < string(name) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len(pl.names) && pl.names[i] == string(name) {
+		return &pl.fields[i], true
+	}
+	return nil, false
+}
+
+// decoderOf returns the decoder of values of the given type. Values of basic
+// kinds are decoded directly, unless the type implements any of the
+// interfaces which are checked by parser.decode.
+func decoderOf(t reflect.Type) decoderFunc {
+	tags, err := structtag.FromValue(reflect.New(t).Elem())
+	if err != nil || tags.DecoderInterface || tags.UnmarshalInterface || tags.RequiredInterface || tags.ValidatorInterface {
+		return (*parser).decode
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return decodeString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decodeUint
+	case reflect.Float32, reflect.Float64:
+		return decodeFloat
+	case reflect.Bool:
+		return decodeBool
+	}
+	return (*parser).decode
+}
+
+func decodeString(p *parser, val reflect.Value) error {
+	if p.current.Type == token.Null {
+		return checkIfEOF(p.next())
+	}
+	s, err := p.readString()
+	val.SetString(s)
+	return err
+}
+
+func decodeInt(p *parser, val reflect.Value) error {
+	if p.current.Type == token.Null {
+		return checkIfEOF(p.next())
+	}
+	n, err := p.readInt()
+	if err != nil {
+		return err
+	}
+	val.SetInt(n)
+	return nil
+}
+
+func decodeUint(p *parser, val reflect.Value) error {
+	if p.current.Type == token.Null {
+		return checkIfEOF(p.next())
+	}
+	n, err := p.readUint()
+	if err != nil {
+		return err
+	}
+	val.SetUint(n)
+	return nil
+}
+
+func decodeFloat(p *parser, val reflect.Value) error {
+	if p.current.Type == token.Null {
+		return checkIfEOF(p.next())
+	}
+	f, err := p.readFloat()
+	if err != nil {
+		return err
+	}
+	val.SetFloat(f)
+	return nil
+}
+
+func decodeBool(p *parser, val reflect.Value) error {
+	if p.current.Type == token.Null {
+		return checkIfEOF(p.next())
+	}
+	b, err := p.readBool()
+	if err != nil {
+		return err
+	}
+	val.SetBool(b)
+	return nil
+}
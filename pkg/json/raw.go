@@ -0,0 +1,71 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// RawMessage is a raw encoded JSON value. It is used to delay decoding a
+// value, such as the payload of an envelope, until its type is known. When
+// decoding, the exact bytes of the value are captured, and when encoding,
+// the bytes are written verbatim, once they have been checked to be a single
+// valid JSON value.
+//
+// As with any other field, a RawMessage field with the `required` option must
+// be present, and must not be null.
+type RawMessage []byte
+
+// ErrInvalidRawMessage is returned when marshalling a RawMessage, which is
+// not a single valid JSON value
+var ErrInvalidRawMessage = errors.New("(required::json) invalid raw message")
+
+// MarshalJSON returns the raw message, or null if it is nil
+func (m RawMessage) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	if err := validRaw(m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRawMessage, err)
+	}
+	return m, nil
+}
+
+// UnmarshalJSON sets the raw message to a copy of the given data
+func (m *RawMessage) UnmarshalJSON(data []byte) error {
+	if m == nil {
+		return errors.New("(required::json) UnmarshalJSON on nil RawMessage")
+	}
+	*m = append((*m)[0:0], data...)
+	return nil
+}
+
+// IsAbsent returns whether the raw message is empty. It is used by
+// required.Validate and MarshalStrict to tell whether a required
+// RawMessage is set.
+func (m RawMessage) IsAbsent() bool {
+	return len(bytes.TrimSpace(m)) == 0
+}
+
+// IsNull returns whether the raw message is the JSON null value
+func (m RawMessage) IsNull() bool {
+	return bytes.Equal(bytes.TrimSpace(m), lexer.NULL)
+}
+
+// validRaw returns an error, if the given data is not a single valid JSON
+// value. Skipping the value checks its full grammar, so all that is left is
+// to check that nothing follows it.
+func validRaw(data []byte) error {
+	l := lexer.NewLexer(data)
+	if _, err := l.SkipValue(); err != nil {
+		return err
+	}
+	if t, err := l.Next(); err != io.EOF {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected data after value: %s", t))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/required"
+)
+
+type Envelope struct {
+	Type    string     `json:"type,required"`
+	Payload RawMessage `json:"payload,required"`
+}
+
+func TestRawMessageUnmarshal(t *testing.T) {
+	payloads := []string{
+		`{"a": ["}", {"b": "\"]"}], "c": null}`,
+		`[1, 2.5e3, true]`,
+		`"text"`,
+		`-12`,
+		`false`,
+	}
+	for _, payload := range payloads {
+		var env Envelope
+		if err := Unmarshal([]byte(`{"type": "t", "payload": `+payload+`}`), &env); err != nil {
+			t.Fatal(err)
+		}
+		if string(env.Payload) != payload {
+			t.Fatalf("expected %s, got %s", payload, env.Payload)
+		}
+	}
+
+	for _, data := range []string{`{"type": "t"}`, `{"type": "t", "payload": null}`} {
+		var env Envelope
+		err := Unmarshal([]byte(data), &env)
+		if err == nil || !strings.Contains(err.Error(), "payload") {
+			t.Fatalf("%s: expected required error, got: %v", data, err)
+		}
+	}
+}
+
+func TestRawMessageContainers(t *testing.T) {
+	var v struct {
+		List []RawMessage          `json:"list"`
+		Map  map[string]RawMessage `json:"map"`
+		Ptr  *RawMessage           `json:"ptr"`
+	}
+	data := `{"list": [{"a": 1}, [2], "3"], "map": {"b": [true, null], "c": {}}, "ptr": "p"}`
+	if err := Unmarshal([]byte(data), &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.List) != 3 || string(v.List[0]) != `{"a": 1}` || string(v.List[1]) != `[2]` || string(v.List[2]) != `"3"` {
+		t.Fatal(v.List)
+	}
+	if string(v.Map["b"]) != `[true, null]` || string(v.Map["c"]) != `{}` {
+		t.Fatal(v.Map)
+	}
+	if v.Ptr == nil || string(*v.Ptr) != `"p"` {
+		t.Fatal(v.Ptr)
+	}
+
+	out, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"list":[{"a": 1},[2],"3"],"map":{"b":[true, null],"c":{}},"ptr":"p"}`
+	if string(out) != expected {
+		t.Fatalf("\nexpected: %s\ngot:      %s", expected, out)
+	}
+}
+
+func TestRawMessageMarshal(t *testing.T) {
+	out, err := Marshal(Envelope{Type: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"type":"t","payload":null}` {
+		t.Fatal(string(out))
+	}
+
+	for _, invalid := range []string{`{"a": 1`, `{"a" 1}`, `1 2`, ``, `tru`} {
+		_, err := Marshal(Envelope{Type: "t", Payload: RawMessage(invalid)})
+		if !errors.Is(err, ErrInvalidRawMessage) {
+			t.Fatalf("%q: expected invalid raw message, got: %v", invalid, err)
+		}
+	}
+
+	if err := required.Validate(Envelope{Type: "t", Payload: RawMessage(`null`)}); err == nil {
+		t.Fatal("expected null payload to be invalid")
+	}
+	if _, err := MarshalStrict(Envelope{Type: "t", Payload: RawMessage(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+}
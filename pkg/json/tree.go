@@ -0,0 +1,144 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+var (
+	// ErrInvalidPath is returned by Tree.Get for paths, which cannot be parsed
+	ErrInvalidPath = errors.New("(required::json) invalid path")
+	// ErrPathNotFound is returned by Tree.Get for paths, which do not exist
+	ErrPathNotFound = errors.New("(required::json) path not found")
+)
+
+// Tree is a JSON document decoded without type information, for ad-hoc
+// inspection of its values by path. Objects are decoded as *OrderedMap, so
+// the tree is encoded in the same order as it was decoded.
+type Tree struct {
+	root interface{}
+}
+
+// ParseTree decodes the given document as a Tree
+func ParseTree(data []byte) (*Tree, error) {
+	t := &Tree{}
+	if err := t.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Root returns the value of the whole document
+func (t *Tree) Root() interface{} {
+	return t.root
+}
+
+// Get returns the value at the given path, such as "a.b[0]", using the same
+// format as the paths of validation errors. The empty path refers to the
+// whole document. Object keys containing '.' or '[' cannot be referred to.
+func (t *Tree) Get(path string) (interface{}, error) {
+	segments, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	v := t.root
+	for i, s := range segments {
+		var ok bool
+		if s.array {
+			v, ok = element(v, s.index)
+		} else {
+			v, ok = member(v, s.field)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, joinPath(segments[:i+1]))
+		}
+	}
+	return v, nil
+}
+
+func member(v interface{}, key string) (interface{}, bool) {
+	switch obj := v.(type) {
+	case *OrderedMap:
+		return obj.Get(key)
+	case map[string]interface{}:
+		v, ok := obj[key]
+		return v, ok
+	}
+	return nil, false
+}
+
+func element(v interface{}, i int) (interface{}, bool) {
+	arr, ok := v.([]interface{})
+	if !ok || i >= len(arr) {
+		return nil, false
+	}
+	return arr[i], true
+}
+
+// splitPath will split the given path into its object keys and array
+// indexes
+func splitPath(path string) ([]segment, error) {
+	var segments []segment
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %s: missing ]", ErrInvalidPath, path)
+			}
+			n, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: %s: invalid index %q", ErrInvalidPath, path, path[i+1:i+end])
+			}
+			segments = append(segments, segment{index: n, array: true})
+			i += end + 1
+			continue
+		case '.':
+			if i == 0 || i == len(path)-1 || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, fmt.Errorf("%w: %s: empty key", ErrInvalidPath, path)
+			}
+			i++
+		}
+		end := strings.IndexAny(path[i:], ".[")
+		if end < 0 {
+			end = len(path) - i
+		}
+		if end == 0 {
+			continue
+		}
+		segments = append(segments, segment{field: path[i : i+end]})
+		i += end
+	}
+	return segments, nil
+}
+
+// MarshalJSON writes the document
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	return marshal(t.root)
+}
+
+// UnmarshalJSON decodes the document
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	p := &parser{lexer: lexer.NewLexer(data), opts: Options{OrderedObjects: true}}
+	if err := p.next(); err != nil {
+		if err == io.EOF {
+			return token.Error(token.ErrInvalidJSON, "empty document")
+		}
+		return err
+	}
+	root, err := p.value()
+	if err != nil {
+		return err
+	}
+	if p.current.Type != token.Unknown {
+		return token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected data after value: %s", p.current))
+	}
+	t.root = root
+	return nil
+}
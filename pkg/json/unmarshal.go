@@ -11,7 +11,8 @@ func Unmarshal(data []byte, v interface{}) error {
 }
 
 type Decoder struct {
-	r io.Reader
+	r    io.Reader
+	opts Options
 }
 
 func NewDecoder(w io.Reader) *Decoder {
@@ -19,9 +20,18 @@ func NewDecoder(w io.Reader) *Decoder {
 }
 
 func (d *Decoder) Decode(v interface{}) error {
-	l, err := lexer.NewLexerReader(d.r)
+	r := d.r
+	if d.opts.MaxBytes > 0 {
+		// reading a single byte more than the limit is enough to tell that the
+		// document is too large, without reading all of it into memory
+		r = io.LimitReader(r, int64(d.opts.MaxBytes)+1)
+	}
+	l, err := lexer.NewLexerReader(r)
 	if err != nil {
 		return err
 	}
-	return Parse(l, v)
+	if err := d.opts.checkBytes(l.Input()); err != nil {
+		return err
+	}
+	return parse(l, v, d.opts)
 }
@@ -0,0 +1,216 @@
+package json
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// ErrUnknownVariant is returned when decoding a value into a registered
+// interface, of which the discriminator is missing or unknown, and when
+// encoding a value, of which the type has not been registered
+var ErrUnknownVariant = errors.New("(required::json) unknown variant")
+
+type variantErr struct {
+	path    string
+	details string
+}
+
+func (err variantErr) Error() string {
+	path := err.path
+	if path == "" {
+		path = "$"
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrUnknownVariant, path, err.details)
+}
+
+func (err variantErr) Unwrap() error {
+	return ErrUnknownVariant
+}
+
+// variants are the concrete types of an interface, selected by the value of
+// the discriminator field
+type variants struct {
+	field  string
+	types  map[string]reflect.Type
+	values map[reflect.Type]string
+}
+
+// variantRegistry holds the variants of every registered interface, as a
+// reflect.Type to *variants map. The registered variants are never modified,
+// but replaced by a copy when another variant is registered, so that they
+// can be read while decoding without locking.
+var (
+	variantRegistry sync.Map
+	variantMu       sync.Mutex
+)
+
+// variantsOf returns the variants registered for the given interface type
+func variantsOf(t reflect.Type) (*variants, bool) {
+	vs, ok := variantRegistry.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return vs.(*variants), true
+}
+
+// RegisterVariant will register the type of v as a variant of the interface
+// I. When decoding an object into a value of type I, the string value of the
+// discriminator field of the object selects the concrete type, which is
+// decoded with all of its tags enforced. When encoding a value of type I,
+// the discriminator field is written, unless it is already written by the
+// concrete type itself, in which case it must have the registered value.
+//
+// Every variant of an interface must use the same discriminator field.
+// RegisterVariant panics if the registration is invalid, and is meant to be
+// called from an init func, though it is safe to call while other values are
+// being decoded or encoded:
+//
+//	json.RegisterVariant[Event]("type", "created", Created{})
+//	json.RegisterVariant[Event]("type", "deleted", &Deleted{})
+func RegisterVariant[I any](discriminator, value string, v I) {
+	iface := reflect.TypeOf((*I)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("json: RegisterVariant of non-interface type %v", iface))
+	}
+	t := reflect.TypeOf(v)
+	if t == nil {
+		panic("json: RegisterVariant of nil value")
+	}
+	variantMu.Lock()
+	defer variantMu.Unlock()
+	vs, ok := variantsOf(iface)
+	if !ok {
+		vs = &variants{field: discriminator}
+	}
+	if vs.field != discriminator {
+		panic(fmt.Sprintf("json: RegisterVariant of %v with discriminator %q, expected %q", iface, discriminator, vs.field))
+	}
+	if _, ok := vs.types[value]; ok {
+		panic(fmt.Sprintf("json: RegisterVariant of %v with duplicate value %q", iface, value))
+	}
+	if _, ok := vs.values[t]; ok {
+		panic(fmt.Sprintf("json: RegisterVariant of %v with duplicate type %v", iface, t))
+	}
+	registered := &variants{
+		field:  discriminator,
+		types:  map[string]reflect.Type{value: t},
+		values: map[reflect.Type]string{t: value},
+	}
+	for value, t := range vs.types {
+		registered.types[value] = t
+		registered.values[t] = value
+	}
+	variantRegistry.Store(iface, registered)
+}
+
+// decodeVariant will decode the object starting at the current token into
+// the given interface value, using the concrete type selected by the
+// discriminator of the object
+func (p *parser) decodeVariant(val reflect.Value, vs *variants) error {
+	data, err := p.skipJSON()
+	if err != nil {
+		return err
+	}
+	name, ok, err := discriminator(data, vs.field)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return variantErr{path: p.Path(), details: fmt.Sprintf("missing discriminator %q", vs.field)}
+	}
+	t, ok := vs.types[name]
+	if !ok {
+		return variantErr{path: p.Path(), details: fmt.Sprintf("%s %q of %v", vs.field, name, val.Type())}
+	}
+	v := reflect.New(t).Elem()
+	if err := p.decodeRaw(v, data); err != nil {
+		return err
+	}
+	val.Set(v)
+	return nil
+}
+
+// discriminator returns the string value of the given member of the object,
+// without decoding any of the other members
+func discriminator(data []byte, field string) (string, bool, error) {
+	l := lexer.NewLexer(data)
+	t, err := l.Next()
+	if err != nil {
+		return "", false, err
+	}
+	if t.Type != token.OpenCurly {
+		return "", false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object, got: %s", t))
+	}
+	for {
+		if t, err = l.Next(); err != nil || t.Type == token.ClosingCurly {
+			return "", false, checkIfEOF(err)
+		}
+		if t.Type != token.String {
+			return "", false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object field, got: %s", t))
+		}
+		key := t.Value
+		if t, err = l.Next(); err != nil || t.Type != token.Colon {
+			return "", false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected colon token: %s", t))
+		}
+		if string(key) == field {
+			if t, err = l.Next(); err != nil || t.Type != token.String {
+				return "", false, token.Error(token.ErrInvalidValue, fmt.Sprintf("discriminator %q must be a string, got: %s", field, t))
+			}
+			return t.ToString(), true, nil
+		}
+		if _, err := l.SkipValue(); err != nil {
+			return "", false, err
+		}
+		if t, err = l.Next(); err == io.EOF || t.Type == token.ClosingCurly {
+			return "", false, nil
+		}
+		if t.Type != token.Comma {
+			return "", false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected } or comma: %s", t))
+		}
+	}
+}
+
+// marshalVariant will write the given concrete value of a registered
+// interface, adding the discriminator field if it is not written by the
+// concrete value itself
+func marshalVariant(val reflect.Value, vs *variants, buf *bytes.Buffer) error {
+	name, ok := vs.values[val.Type()]
+	if !ok {
+		return variantErr{details: fmt.Sprintf("unregistered type %v", val.Type())}
+	}
+	var obj bytes.Buffer
+	if err := _marshal(val, &obj); err != nil {
+		return err
+	}
+	data := obj.Bytes()
+	if len(data) == 0 || data[0] != '{' {
+		return variantErr{details: fmt.Sprintf("%v must be encoded as an object, got: %s", val.Type(), data)}
+	}
+	written, found, err := discriminator(data, vs.field)
+	if err != nil {
+		return err
+	}
+	if found {
+		if written != name {
+			return variantErr{details: fmt.Sprintf("%v is encoded with %s %q, expected %q", val.Type(), vs.field, written, name)}
+		}
+		buf.Write(data)
+		return nil
+	}
+	buf.WriteByte('{')
+	writeString(buf, vs.field)
+	buf.WriteRune(colon)
+	writeString(buf, name)
+	if rest := bytes.TrimSpace(data[1:]); len(rest) > 0 && rest[0] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(data[1:])
+	return nil
+}
@@ -0,0 +1,198 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type Event interface {
+	Kind() string
+}
+
+type Created struct {
+	ID   int64  `json:"id,required"`
+	Name string `json:"name" validate:"min=1"`
+}
+
+func (Created) Kind() string { return "created" }
+
+type Deleted struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id,required"`
+}
+
+func (*Deleted) Kind() string { return "deleted" }
+
+type Renamed struct{}
+
+func (Renamed) Kind() string { return "renamed" }
+
+func init() {
+	RegisterVariant[Event]("type", "created", Created{})
+	RegisterVariant[Event]("type", "deleted", &Deleted{})
+}
+
+type EventLog struct {
+	Latest Event            `json:"latest"`
+	Events []Event          `json:"events"`
+	ByName map[string]Event `json:"by_name"`
+}
+
+func TestVariantUnmarshal(t *testing.T) {
+	data := `{
+		"latest": {"id": 1, "type": "created", "name": "a", "extra": {"type": "deleted"}},
+		"events": [{"type": "deleted", "id": 2}, {"type": "created", "id": 3}, null],
+		"by_name": {"x": {"type": "created", "id": 4}}
+	}`
+	var log EventLog
+	if err := Unmarshal([]byte(data), &log); err != nil {
+		t.Fatal(err)
+	}
+	expected := EventLog{
+		Latest: Created{ID: 1, Name: "a"},
+		Events: []Event{&Deleted{Type: "deleted", ID: 2}, Created{ID: 3}, nil},
+		ByName: map[string]Event{"x": Created{ID: 4}},
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Fatalf("\nexpected: %+v\ngot:      %+v", expected, log)
+	}
+
+	var event Event
+	if err := Unmarshal([]byte(`{"type": "deleted", "id": 5}`), &event); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(event, &Deleted{Type: "deleted", ID: 5}) {
+		t.Fatal(event)
+	}
+}
+
+func TestVariantUnmarshalErrors(t *testing.T) {
+	tt := []struct {
+		data string
+		err  error
+		msg  string
+	}{
+		{`{"latest": {"id": 1}}`, ErrUnknownVariant, `latest: missing discriminator "type"`},
+		{`{"events": [{"type": "renamed"}]}`, ErrUnknownVariant, `events[0]: type "renamed"`},
+		{`{"latest": {"type": 1}}`, nil, "must be a string"},
+		{`{"latest": {"type": "created"}}`, nil, "RequiredInterface field missing: id"},
+		{`{"latest": {"type": "created", "id": 1, "name": ""}}`, nil, "latest.name: min=1"},
+		{`{"latest": [1]}`, nil, "expected object"},
+	}
+	for _, tc := range tt {
+		var log EventLog
+		err := Unmarshal([]byte(tc.data), &log)
+		if err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Fatalf("%s: expected error containing %q, got: %v", tc.data, tc.msg, err)
+		}
+		if tc.err != nil && !errors.Is(err, tc.err) {
+			t.Fatalf("%s: expected %v, got: %v", tc.data, tc.err, err)
+		}
+	}
+}
+
+func TestVariantMarshal(t *testing.T) {
+	log := EventLog{
+		Latest: Created{ID: 1, Name: "a"},
+		Events: []Event{&Deleted{Type: "deleted", ID: 3}, nil},
+	}
+	data, err := Marshal(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"latest":{"type":"created","id":1,"name":"a"},"events":[{"type":"deleted","id":3},null],"by_name":null}`
+	if string(data) != expected {
+		t.Fatalf("\nexpected: %s\ngot:      %s", expected, data)
+	}
+
+	for _, event := range []Event{Renamed{}, &Deleted{ID: 2}} {
+		if _, err := Marshal(EventLog{Latest: event}); !errors.Is(err, ErrUnknownVariant) {
+			t.Fatalf("expected unknown variant, got: %v", err)
+		}
+	}
+
+	// the discriminator is written, so the value can be decoded again
+	var decoded EventLog
+	data, _ = Marshal(EventLog{Latest: Created{ID: 1, Name: "a"}})
+	if err := Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Latest != (Created{ID: 1, Name: "a"}) {
+		t.Fatal(decoded.Latest)
+	}
+}
+
+func TestRegisterVariantPanics(t *testing.T) {
+	registrations := []func(){
+		func() { RegisterVariant[Event]("kind", "renamed", Renamed{}) },
+		func() { RegisterVariant[Event]("type", "created", Renamed{}) },
+		func() { RegisterVariant[Event]("type", "other", Created{}) },
+		func() { RegisterVariant[Created]("type", "created", Created{}) },
+		func() { RegisterVariant[Event]("type", "nil", nil) },
+	}
+	for i, register := range registrations {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("registration %d did not panic", i)
+				}
+			}()
+			register()
+		}()
+	}
+}
+
+type Shape interface {
+	Sides() int
+}
+
+type Triangle struct{}
+
+func (Triangle) Sides() int { return 3 }
+
+type Square struct{}
+
+func (Square) Sides() int { return 4 }
+
+type Hexagon struct{}
+
+func (Hexagon) Sides() int { return 6 }
+
+func TestRegisterVariantConcurrently(t *testing.T) {
+	// variants are registered while other variants are decoded and encoded.
+	// Run with -race.
+	shapes := map[string]Shape{"triangle": Triangle{}, "square": Square{}, "hexagon": Hexagon{}}
+	var wg sync.WaitGroup
+	for name, shape := range shapes {
+		wg.Add(2)
+		go func(name string, shape Shape) {
+			defer wg.Done()
+			RegisterVariant[Shape]("shape", name, shape)
+		}(name, shape)
+		go func() {
+			defer wg.Done()
+			var event Event
+			if err := Unmarshal([]byte(`{"type": "created", "id": 1}`), &event); err != nil {
+				t.Error(err)
+			}
+			if _, err := Marshal(&event); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for name, shape := range shapes {
+		var decoded Shape
+		if err := Unmarshal([]byte(fmt.Sprintf(`{"shape": %q}`, name)), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded != shape {
+			t.Fatalf("%s: got %v", name, decoded)
+		}
+	}
+}
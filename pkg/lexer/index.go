@@ -0,0 +1,115 @@
+package lexer
+
+import "sort"
+
+// indexThreshold is the size of the input, from which the Lexer builds an
+// Index, the first time a container is skipped
+var indexThreshold = 64 << 10
+
+// Index is a structural index of a JSON document, which is built in a single
+// pass over the input, and records the offset of the matching closing brace
+// or bracket of every opening brace or bracket, outside of strings. With an
+// Index, skipping a container does not require lexing its contents.
+type Index struct {
+	opening []int
+	closing []int
+}
+
+// NewIndex builds the Index of the given input. If the braces and brackets
+// of the input do not match, or a string is not terminated, false is
+// returned.
+func NewIndex(data []byte) (*Index, bool) {
+	idx := &Index{}
+	var stack []int
+	for i := indexStructural(data, 0); i < len(data); i = indexStructural(data, i+1) {
+		switch data[i] {
+		case '"':
+			if i = closingQuote(data, i+1); i >= len(data) {
+				return nil, false
+			}
+		case '{', '[':
+			stack = append(stack, len(idx.opening))
+			idx.opening = append(idx.opening, i)
+			idx.closing = append(idx.closing, -1)
+		default:
+			if len(stack) == 0 {
+				return nil, false
+			}
+			open := stack[len(stack)-1]
+			if data[idx.opening[open]] != opposite(data[i]) {
+				return nil, false
+			}
+			stack = stack[:len(stack)-1]
+			idx.closing[open] = i
+		}
+	}
+	return idx, len(stack) == 0
+}
+
+func opposite(b byte) byte {
+	if b == '}' {
+		return '{'
+	}
+	return '['
+}
+
+// Closing returns the offset of the brace or bracket, which closes the brace
+// or bracket at the given offset
+func (idx *Index) Closing(offset int) (int, bool) {
+	i := sort.SearchInts(idx.opening, offset)
+	if i < len(idx.opening) && idx.opening[i] == offset {
+		return idx.closing[i], true
+	}
+	return 0, false
+}
+
+// SkipContainer will skip the contents of the object or array, which begins
+// with the token most recently returned by Next or Scan, so that the closing
+// brace or bracket is the most recent token. For large inputs, this uses an
+// Index of the input, in which case true is returned. Otherwise, false is
+// returned and the Lexer is left unchanged.
+func (l *Lexer) SkipContainer() bool {
+	closing, ok := l.closing(l.start)
+	if !ok {
+		return false
+	}
+	l.stack.Pop()
+	l.index, l.start = closing, closing
+	l.valueStart, l.valueEnd = closing, closing+1
+	return true
+}
+
+// closing returns the offset of the brace or bracket, which closes the brace
+// or bracket at the given offset, using the Index of the input. If the input
+// is too small to be indexed, or cannot be indexed, false is returned.
+//
+// The Index only matches braces, brackets and quotes, so the first time it
+// is built, every value of the input is validated, as SkipValue would. Once
+// built, skipping a container never has to look at its contents again.
+// Lenient inputs are never indexed, as quotes and comments would not match.
+func (l *Lexer) closing(offset int) (int, bool) {
+	if l.structure == nil {
+		if l.lenient || len(l.input) < indexThreshold || l.unindexable {
+			return 0, false
+		}
+		var ok bool
+		if l.structure, ok = NewIndex(l.input); !ok || !l.valid() {
+			// the error is reported, once the lexer reaches it
+			l.structure, l.unindexable = nil, true
+			return 0, false
+		}
+	}
+	return l.structure.Closing(offset)
+}
+
+// valid returns whether every value of the input is valid JSON
+func (l *Lexer) valid() bool {
+	for i := skipSpaces(l.input, 0); i < len(l.input); i = skipSpaces(l.input, i) {
+		end, err := l.skip(i)
+		if err != nil {
+			return false
+		}
+		i = end
+	}
+	return true
+}
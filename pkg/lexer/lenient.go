@@ -0,0 +1,216 @@
+package lexer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+var (
+	INFINITY = []byte("Infinity")
+	NAN      = []byte("NaN")
+)
+
+// SetLenient will enable or disable the lenient mode of the Lexer, which
+// accepts a subset of JSON5, for hand-edited documents such as configuration
+// files. In the lenient mode:
+//
+//   - // and /* */ comments are treated as whitespace.
+//   - trailing commas in objects and arrays are ignored.
+//   - strings may be single-quoted, and \' is a valid escape sequence.
+//   - object keys may be unquoted identifiers of ASCII letters, digits, _
+//     and $, which do not start with a digit.
+//   - numbers may be hexadecimal, such as 0x1F, and have a leading +.
+//   - Infinity and NaN are numbers, with an optional sign.
+//
+// The tokens are returned as if the document was JSON. Strings and object
+// keys are String tokens, and hexadecimal numbers and numbers with a leading
+// + are returned as decimal Integer or Float tokens, so the same parser is
+// used for both JSON and lenient documents.
+func (l *Lexer) SetLenient(lenient bool) {
+	l.lenient = lenient
+}
+
+// Lenient returns whether the Lexer is in the lenient mode
+func (l *Lexer) Lenient() bool {
+	return l.lenient
+}
+
+func (l *Lexer) scanLenient() (token.TokenType, error) {
+	i, err := l.skipComments(l.index + 1)
+	if err != nil {
+		return token.Unknown, err
+	}
+	if i < len(l.input) {
+		switch b := l.input[i]; {
+		case l.isKey() && isIdentifierStart(b):
+			return l.scanIdentifier(i), nil
+		case b == '\'':
+			l.index, l.start = i, i
+			return l.scanSingleQuoted()
+		case b == ',' && l.last != token.OpenCurly && l.last != token.OpenBrace && l.last != token.Comma:
+			next, err := l.skipComments(i + 1)
+			if err != nil {
+				return token.Unknown, err
+			}
+			if next < len(l.input) && (l.input[next] == '}' || l.input[next] == ']') {
+				// the trailing comma is ignored, returning the closing brace
+				i = next
+			}
+		case b == '+' || b == 'I' || b == 'N' || isHex(l.input, i) ||
+			b == '-' && i+1 < len(l.input) && (l.input[i+1] == 'I' || l.input[i+1] == 'N' || isHex(l.input, i+1)):
+			return l.scanLenientNumber(i)
+		}
+	}
+	l.index = i - 1
+	return l.scan()
+}
+
+// skipComments returns the offset of the first byte of the input, from
+// offset i, which is neither whitespace nor part of a comment
+func (l *Lexer) skipComments(i int) (int, error) {
+	for i = skipSpaces(l.input, i); i+1 < len(l.input) && l.input[i] == '/'; i = skipSpaces(l.input, i) {
+		switch l.input[i+1] {
+		case '/':
+			end := bytes.IndexByte(l.input[i:], '\n')
+			if end < 0 {
+				return len(l.input), nil
+			}
+			i += end + 1
+		case '*':
+			end := bytes.Index(l.input[i+2:], []byte("*/"))
+			if end < 0 {
+				return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unterminated comment at offset %d", i))
+			}
+			i += end + 4
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+// isKey returns whether the next token is the key of an object member
+func (l *Lexer) isKey() bool {
+	return l.stack.Peek() == '{' && (l.last == token.OpenCurly || l.last == token.Comma)
+}
+
+func isIdentifierStart(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_' || b == '$'
+}
+
+func isIdentifier(b byte) bool {
+	return isIdentifierStart(b) || b >= '0' && b <= '9'
+}
+
+// isHex returns whether a hexadecimal number begins at offset i
+func isHex(data []byte, i int) bool {
+	return i+1 < len(data) && data[i] == '0' && (data[i+1] == 'x' || data[i+1] == 'X')
+}
+
+func isHexDigit(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F'
+}
+
+func (l *Lexer) scanIdentifier(i int) token.TokenType {
+	end := i + 1
+	for end < len(l.input) && isIdentifier(l.input[end]) {
+		end++
+	}
+	l.start, l.index = i, end-1
+	l.valueStart, l.valueEnd = i, end
+	return token.String
+}
+
+func (l *Lexer) scanSingleQuoted() (token.TokenType, error) {
+	l.valueStart = l.index + 1
+	i := l.valueStart
+	for ; i < len(l.input) && l.input[i] != '\''; i++ {
+		if l.input[i] == '\\' {
+			i++
+		}
+	}
+	if i >= len(l.input) {
+		return token.Unknown, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unterminated string at offset %d", l.start))
+	}
+	l.index, l.valueEnd = i, i
+	return l.scanned()
+}
+
+// scanLenientNumber scans a number beginning at offset i, which may have a
+// leading +, be hexadecimal, or be Infinity or NaN
+func (l *Lexer) scanLenientNumber(i int) (token.TokenType, error) {
+	l.start = i
+	start := i
+	if b := l.input[i]; b == '+' || b == '-' {
+		i++
+		if b == '+' {
+			start = i
+		}
+	}
+	var (
+		end int
+		t   token.TokenType
+	)
+	switch {
+	case literal(l.input, i, INFINITY):
+		end, t = i+len(INFINITY), token.Float
+	case literal(l.input, i, NAN):
+		end, t = i+len(NAN), token.Float
+	case isHex(l.input, i):
+		for end = i + 2; end < len(l.input) && isHexDigit(l.input[end]); end++ {
+		}
+		n, err := strconv.ParseUint(string(l.input[i+2:end]), 16, 64)
+		if err != nil {
+			return token.Unknown, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid hexadecimal number at offset %d: %q", l.start, l.input[l.start:end]))
+		}
+		// the text is allocated for every number, as the values of tokens
+		// returned by Next must never change
+		text := make([]byte, 0, 21)
+		if l.input[l.start] == '-' {
+			text = append(text, '-')
+		}
+		l.text, t = strconv.AppendUint(text, n, 10), token.Integer
+	default:
+		if end, t = number(l.input, i); t == token.Unknown {
+			return t, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid number at offset %d: %q", l.start, l.input[l.start:min(end+1, len(l.input))]))
+		}
+	}
+	l.index = end - 1
+	l.valueStart, l.valueEnd = start, end
+	return t, nil
+}
+
+// skipTokens will skip the value, which begins with the given token most
+// recently returned by Scan, one token at a time. This is used in the lenient
+// mode, in which the tokens of skipped values are validated as they are
+// scanned, and the structure once the value is converted into JSON.
+func (l *Lexer) skipTokens(start int, t token.TokenType) ([]byte, error) {
+	var err error
+	if t.IsEnding() || t == token.Colon || t == token.Comma {
+		return nil, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected %s at offset %d, expected value", t, start))
+	}
+	for depth := 0; ; {
+		if t.IsOpening() {
+			depth++
+		} else if t.IsEnding() {
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+		if t, err = l.Scan(); err != nil {
+			if err == io.EOF {
+				return nil, token.Error(token.ErrMissingBrace, string(l.input[start]))
+			}
+			return nil, err
+		}
+	}
+	end := l.index + 1
+	l.start, l.text = start, nil
+	l.valueStart, l.valueEnd = start, end
+	return l.input[start:end], nil
+}
@@ -0,0 +1,107 @@
+package lexer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+func TestLenient(t *testing.T) {
+	l := NewLexer([]byte(`// configuration
+	{
+		name: 'it''s', /* block
+		comment */ "quoted": "\'",
+		$id_2: [0x1F, -0XfF, +1.5, +Infinity, -Infinity, NaN, 1,],
+		true: null, // keys may be reserved words
+	}`))
+	l.SetLenient(true)
+	expected := []struct {
+		t     token.TokenType
+		value string
+	}{
+		{token.OpenCurly, "{"},
+		{token.String, "name"}, {token.Colon, ":"}, {token.String, "it"},
+	}
+	for _, e := range expected {
+		tt, err := l.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt != e.t || string(l.Bytes()) != e.value {
+			t.Fatalf("expected %v %q, got %v %q", e.t, e.value, tt, l.Bytes())
+		}
+	}
+	// adjacent strings are not concatenated
+	if tt, _ := l.Scan(); tt != token.String {
+		t.Fatalf("expected string, got: %v", tt)
+	}
+	if tt, err := l.Scan(); tt != token.Comma || err != nil {
+		t.Fatalf("expected comma, got: %v %v", tt, err)
+	}
+	expected = []struct {
+		t     token.TokenType
+		value string
+	}{
+		{token.String, "quoted"}, {token.Colon, ":"}, {token.String, "'"}, {token.Comma, ","},
+		{token.String, "$id_2"}, {token.Colon, ":"}, {token.OpenBrace, "["},
+		{token.Integer, "31"}, {token.Comma, ","}, {token.Integer, "-255"}, {token.Comma, ","},
+		{token.Float, "1.5"}, {token.Comma, ","}, {token.Float, "Infinity"}, {token.Comma, ","},
+		{token.Float, "-Infinity"}, {token.Comma, ","}, {token.Float, "NaN"}, {token.Comma, ","},
+		{token.Integer, "1"}, {token.ClosingBrace, "]"}, {token.Comma, ","},
+		{token.String, "true"}, {token.Colon, ":"}, {token.Null, "null"}, {token.ClosingCurly, "}"},
+	}
+	for _, e := range expected {
+		tt, err := l.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt != e.t || string(l.Bytes()) != e.value {
+			t.Fatalf("expected %v %q, got %v %q", e.t, e.value, tt, l.Bytes())
+		}
+	}
+	if _, err := l.Scan(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
+
+func TestLenientInvalid(t *testing.T) {
+	for _, input := range []string{
+		`[ident]`, `{a: ident}`, `[0x]`, `[0x10000000000000000]`, `['unterminated]`,
+		`[1] /* unterminated`, `[1 / 2]`, `{1a: 1}`, `[+]`, `[Inf]`, `['\x']`,
+	} {
+		l := NewLexer([]byte(input))
+		l.SetLenient(true)
+		if err := scanAll(l); err == io.EOF {
+			t.Fatalf("%s: expected error", input)
+		}
+	}
+	for _, input := range []string{`{a: 1}`, `['a']`, `[0x1]`, `[+1]`, `[NaN]`, `[1] // comment`, `["\'"]`} {
+		if err := scanAll(NewLexer([]byte(input))); err == io.EOF {
+			t.Fatalf("%s: expected error, when not lenient", input)
+		}
+	}
+}
+
+func scanAll(l *Lexer) error {
+	for {
+		if _, err := l.Scan(); err != nil {
+			return err
+		}
+	}
+}
+
+func TestLenientSkip(t *testing.T) {
+	l := NewLexer([]byte(`{a: [1, /* ] */ 'b',], "c": {d: 0x1}, } // end`))
+	l.SetLenient(true)
+	value, err := l.SkipValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{a: [1, /* ] */ 'b',], "c": {d: 0x1}, }`; string(value) != expected {
+		t.Fatalf("expected %q, got %q", expected, value)
+	}
+	if _, err := l.Next(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"fmt"
 	"io"
 	"io/ioutil"
 
@@ -9,8 +10,26 @@ import (
 
 type Lexer struct {
 	index int
+	start int
 	input []byte
-	stack *Stack
+	stack Stack
+	// braces is the initial storage of the stack, so that a Lexer is
+	// created with a single allocation
+	braces [16]byte
+	// valueStart and valueEnd are the offsets of the value of the token most
+	// recently returned by Scan
+	valueStart, valueEnd int
+	// text is the unescaped value of the string most recently returned by
+	// Scan, if the string contains any escape sequences
+	text []byte
+	// lenient is set for the lenient mode, in which last is the type of the
+	// token most recently returned by Scan
+	lenient bool
+	last    token.TokenType
+	// structure is the Index of large inputs, which is built the first time
+	// a container is skipped, unless the input is unindexable
+	structure   *Index
+	unindexable bool
 }
 
 func NewLexerReader(r io.Reader) (*Lexer, error) {
@@ -22,11 +41,12 @@ func NewLexerReader(r io.Reader) (*Lexer, error) {
 }
 
 func NewLexer(input []byte) *Lexer {
-	return &Lexer{
+	l := &Lexer{
 		input: input,
 		index: -1,
-		stack: NewStack(10),
 	}
+	l.stack.stack = l.braces[:]
+	return l
 }
 
 func (l *Lexer) Previous() string {
@@ -40,72 +60,16 @@ func max(a, b int) int {
 	return b
 }
 
-func (l *Lexer) EOF() bool {
-	return l.index >= len(l.input)
-}
-
-func (l *Lexer) SkipValue() []byte {
-	if l.index == -1 {
-		l.index = 0
-	}
-	l.skipWhile(':')
-	l.skipWhitespace()
-	var (
-		stack   int
-		start   = l.index
-		opening byte
-	)
-
-	if l.value() == '"' {
-		t, err := l.readString()
-		if err != nil {
-			return nil
-		}
-		return t.Value
-	}
-
-	closing, ok := token.BraceOpposites[l.value()]
-	if !ok {
-		closing = ','
-	} else {
-		opening = l.value()
-	}
-
-	for l.next() {
-		if l.value() == opening {
-			stack++
-		}
-		if l.value() == closing {
-			if stack == 0 {
-				return l.input[start : l.index+1]
-			} else {
-				stack--
-			}
-		}
-	}
-	if l.input[start] == '"' {
-		// TODO what in the world???
-	}
-
-	if l.input[l.index-1] == '}' {
-		return l.input[start : l.index-1]
-	}
-	if l.index == len(l.input) {
-		return l.input[start:l.index]
+func min(a, b int) int {
+	if a < b {
+		return a
 	}
-	return l.input[start : l.index+1]
-}
-
-func (l *Lexer) skipWhitespace() {
-	l.skipWhile(' ')
+	return b
 }
 
-func (l *Lexer) skipWhile(b byte) {
-	for !l.EOF() && l.value() == b {
-		if !l.next() {
-			return
-		}
-	}
+// Input returns the entire input of the Lexer
+func (l *Lexer) Input() []byte {
+	return l.input
 }
 
 func (l *Lexer) skipTo(b byte) {
@@ -122,41 +86,89 @@ var (
 	NULL  = []byte("null")
 )
 
+// Next reads the next token of the input. At the end of the input, io.EOF
+// is returned.
 func (l *Lexer) Next() (token.Token, error) {
-	if !l.next() {
-		return token.Empty, l.isValid()
-	}
-	switch l.value() {
-	case token.Space, token.Tab, token.NewLine:
-		return l.Next()
-	case token.Quotation:
-		return l.readString()
-	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		t := l.readNumber()
-		l.index--
-		return t, nil
-	case 't':
-		l.index += len("rue")
-		return token.Token{Value: TRUE, Type: token.Boolean}, nil
-	case 'f':
-		l.index += len("alse")
-		return token.Token{Value: FALSE, Type: token.Boolean}, nil
-	case 'n':
-		l.index += len("ull")
-		return token.Token{Value: NULL, Type: token.Null}, nil
-	default:
-		t := token.NewToken(l.input, l.index)
-		if t.Type.IsOpening() {
-			l.stack.Push(l.value())
+	t, err := l.Scan()
+	switch t {
+	case token.Unknown:
+		if err != nil {
+			return token.Empty, err
+		}
+	case token.Boolean:
+		if l.input[l.start] == 't' {
+			return token.Token{Value: TRUE, Type: t}, nil
 		}
-		if t.Type.IsEnding() {
-			opposite := l.stack.Pop()
-			if token.BraceOpposites[opposite] != t.Value[0] {
-				return t, token.Error(token.ErrUnmatchedBrace, string(l.input[:l.index]))
+		return token.Token{Value: FALSE, Type: t}, nil
+	case token.Null:
+		return token.Token{Value: NULL, Type: t}, nil
+	}
+	return token.Token{Value: l.Bytes(), Type: t}, err
+}
+
+// Scan reads the next token of the input, like Next, but only returns the
+// type of the token. The value of the token is returned by Bytes, which
+// refers to the input, so no Token is built while scanning.
+func (l *Lexer) Scan() (token.TokenType, error) {
+	l.text = nil
+	if l.lenient {
+		t, err := l.scanLenient()
+		l.last = t
+		return t, err
+	}
+	return l.scan()
+}
+
+func (l *Lexer) scan() (token.TokenType, error) {
+	if l.index = skipSpaces(l.input, l.index+1); l.index < len(l.input) {
+		l.start = l.index
+		l.valueStart, l.valueEnd = l.index, l.index+1
+		switch b := l.input[l.index]; b {
+		case token.Quotation:
+			return l.scanString()
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			end, t := number(l.input, l.index)
+			if t == token.Unknown {
+				return t, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid number at offset %d: %q", l.start, l.input[l.start:min(end+1, len(l.input))]))
+			}
+			l.index, l.valueEnd = end-1, end
+			return t, nil
+		case 't':
+			return l.scanLiteral(TRUE, token.Boolean)
+		case 'f':
+			return l.scanLiteral(FALSE, token.Boolean)
+		case 'n':
+			return l.scanLiteral(NULL, token.Null)
+		default:
+			t := token.TypeOf(b)
+			if t == token.Unknown {
+				return t, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected character %q at offset %d", b, l.index))
 			}
+			if t.IsOpening() {
+				l.stack.Push(b)
+			}
+			if t.IsEnding() {
+				if l.stack.IsEmpty() {
+					return t, token.Error(token.ErrUnmatchedBrace, fmt.Sprintf("unexpected %q at offset %d", b, l.index))
+				}
+				opposite := l.stack.Pop()
+				if token.BraceOpposites[opposite] != b {
+					return t, token.Error(token.ErrUnmatchedBrace, string(l.input[:l.index]))
+				}
+			}
+			return t, nil
 		}
-		return t, nil
 	}
+	return token.Unknown, l.isValid()
+}
+
+// Bytes returns the value of the token most recently returned by Scan. For
+// strings, the value does not include the quotes, and is unescaped.
+func (l *Lexer) Bytes() []byte {
+	if l.text != nil {
+		return l.text
+	}
+	return l.input[l.valueStart:l.valueEnd]
 }
 
 func (l *Lexer) next() bool {
@@ -172,38 +184,87 @@ func (l *Lexer) previous() byte {
 	return l.input[l.index-1]
 }
 
-func (l *Lexer) readNumber() token.Token {
-	tokenType := token.Integer
-	start := l.index
-	for l.next() {
-		switch l.value() {
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		case '.':
-			tokenType = token.Float
-		default:
-			return token.Token{
-				Value: l.input[start:l.index],
-				Type:  tokenType,
-			}
+// number returns the offset immediately following the number, which starts
+// at offset i, and whether the number is an Integer or a Float. If the number
+// is not valid, as defined by RFC 8259, Unknown is returned.
+func number(data []byte, i int) (int, token.TokenType) {
+	if data[i] == '-' {
+		i++
+	}
+	switch {
+	case i >= len(data):
+		return i, token.Unknown
+	case data[i] == '0':
+		i++
+	case data[i] >= '1' && data[i] <= '9':
+		i = digits(data, i+1)
+	default:
+		return i, token.Unknown
+	}
+	t := token.Integer
+	if i < len(data) && data[i] == '.' {
+		if end := digits(data, i+1); end > i+1 {
+			i, t = end, token.Float
+		} else {
+			return end, token.Unknown
+		}
+	}
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		i++
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			i++
+		}
+		if end := digits(data, i); end > i {
+			i, t = end, token.Float
+		} else {
+			return end, token.Unknown
 		}
 	}
-	return token.Token{
-		Value: l.input[start:l.index],
-		Type:  tokenType,
+	return i, t
+}
+
+// digits returns the offset of the first byte of data, from offset i, which
+// is not a digit
+func digits(data []byte, i int) int {
+	for ; i < len(data) && data[i] >= '0' && data[i] <= '9'; i++ {
 	}
+	return i
 }
 
-func (l *Lexer) readString() (token.Token, error) {
-	start := l.index + 1
-	for l.next() {
-		if l.value() == token.Quotation && l.previous() != token.Escape {
-			return token.Token{
-				Value: l.input[start:l.index],
-				Type:  token.String,
-			}, nil
-		}
+// literal returns whether the given literal begins at offset i
+func literal(data []byte, i int, lit []byte) bool {
+	return i+len(lit) <= len(data) && string(data[i:i+len(lit)]) == string(lit)
+}
+
+func (l *Lexer) scanLiteral(lit []byte, t token.TokenType) (token.TokenType, error) {
+	if !literal(l.input, l.index, lit) {
+		return token.Unknown, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid literal at offset %d, expected %s", l.index, lit))
+	}
+	l.index += len(lit) - 1
+	l.valueEnd = l.index + 1
+	return t, nil
+}
+
+func (l *Lexer) scanString() (token.TokenType, error) {
+	l.valueStart = l.index + 1
+	if l.index = closingQuote(l.input, l.valueStart); l.index >= len(l.input) {
+		return token.Unknown, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unterminated string at offset %d", l.start))
+	}
+	l.valueEnd = l.index
+	return l.scanned()
+}
+
+// scanned will validate the string, which has been scanned, and unescape it,
+// if it contains any escape sequences
+func (l *Lexer) scanned() (token.TokenType, error) {
+	escaped, err := validString(l.input[l.valueStart:l.valueEnd], l.lenient)
+	if err != nil {
+		return token.Unknown, err
+	}
+	if escaped {
+		l.text = unescape(l.input[l.valueStart:l.valueEnd])
 	}
-	return token.Empty, token.Error(token.ErrInvalidJSON, string(l.input[:l.index]))
+	return token.String, nil
 }
 
 func (l *Lexer) isValid() error {
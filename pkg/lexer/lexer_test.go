@@ -2,9 +2,7 @@ package lexer
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
-	"strings"
 	"testing"
 
 	"github.com/Pungyeon/required/pkg/token"
@@ -40,7 +38,10 @@ func TestScanValue(t *testing.T) {
 	"float": 3.2
 }`))
 	lexer.skipTo(':')
-	val := lexer.SkipValue()
+	val, err := lexer.SkipValue()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if string(val) != `{
 		"bar": "value" 
@@ -49,9 +50,10 @@ func TestScanValue(t *testing.T) {
 	}
 	lexer.skipTo(':')
 
-	fmt.Println(string(lexer.input[lexer.index:]))
-
-	val = lexer.SkipValue()
+	val, err = lexer.SkipValue()
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	var floaty float64
 	if err := json.Unmarshal(val, &floaty); err != nil {
@@ -67,12 +69,61 @@ func TestScanValue(t *testing.T) {
 
 	lexer.skipTo(':')
 
-	val = lexer.SkipValue()
-	if strings.TrimSpace(string(val)) != "2006-01-02T15:04:05" {
+	val, err = lexer.SkipValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(val) != `"2006-01-02T15:04:05"` {
 		t.Fatal(string(val))
 	}
 }
 
+func TestScan(t *testing.T) {
+	l := NewLexer([]byte(`{"foo": [1, -2.5, {"bar": "a\"b"}, true, null]}`))
+	expected := []struct {
+		t     token.TokenType
+		value string
+	}{
+		{token.OpenCurly, "{"}, {token.String, "foo"}, {token.Colon, ":"}, {token.OpenBrace, "["},
+		{token.Integer, "1"}, {token.Comma, ","}, {token.Float, "-2.5"}, {token.Comma, ","},
+		{token.OpenCurly, "{"}, {token.String, "bar"}, {token.Colon, ":"}, {token.String, `a"b`},
+		{token.ClosingCurly, "}"}, {token.Comma, ","}, {token.Boolean, "true"}, {token.Comma, ","},
+		{token.Null, "null"}, {token.ClosingBrace, "]"}, {token.ClosingCurly, "}"},
+	}
+	for _, e := range expected {
+		tt, err := l.Scan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt != e.t || string(l.Bytes()) != e.value {
+			t.Fatalf("expected %v %q, got %v %q", e.t, e.value, tt, l.Bytes())
+		}
+	}
+	if _, err := l.Scan(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
+
+func TestScanAllocations(t *testing.T) {
+	data := []byte(`{"foo": [1, 2, {"bar": "baz"}, true], "nested": [[[[{"a": null}]]]]}`)
+	allocs := testing.AllocsPerRun(100, func() {
+		l := NewLexer(data)
+		for {
+			if _, err := l.Scan(); err != nil {
+				if err != io.EOF {
+					t.Fatal(err)
+				}
+				return
+			}
+			_ = l.Bytes()
+		}
+	})
+	// the only allocation is the Lexer itself
+	if allocs > 1 {
+		t.Fatalf("%v allocations", allocs)
+	}
+}
+
 func BenchmarkLexerStreamPerformance(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		l := NewLexer([]byte(`{"foo": [1, 2, {"bar": 2}, true]}`))
@@ -82,3 +133,51 @@ func BenchmarkLexerStreamPerformance(b *testing.B) {
 		}
 	}
 }
+
+func TestScanStrings(t *testing.T) {
+	tt := []struct {
+		input string
+		value string
+		err   bool
+	}{
+		{`"plain"`, "plain", false},
+		{`"\"\\\/\b\f\n\r\t"`, "\"\\/\b\f\n\r\t", false},
+		{`"aé€"`, "aé€", false},
+		{`"😀"`, "😀", false},
+		{`"\ud83d"`, "�", false},
+		{`"\ude00\ud83d x"`, "�� x", false},
+		{`"a very long string, which is scanned a word at a time, \n"`, "a very long string, which is scanned a word at a time, \n", false},
+		{"\"tab\t\"", "", true},
+		{"\"a very long string, with a control character\x01\"", "", true},
+		{`"\x"`, "", true},
+		{`"\u12"`, "", true},
+		{`"\u12g4"`, "", true},
+		{`"unterminated`, "", true},
+	}
+	for _, tc := range tt {
+		l := NewLexer([]byte(tc.input))
+		tt, err := l.Scan()
+		if tc.err {
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.input)
+			}
+			continue
+		}
+		if err != nil || tt != token.String || string(l.Bytes()) != tc.value {
+			t.Fatalf("%s: expected %q, got %v %q: %v", tc.input, tc.value, tt, l.Bytes(), err)
+		}
+	}
+}
+
+func TestScanInvalid(t *testing.T) {
+	for _, input := range []string{"tru", "nul", "falsy", "-", "1.", "1e", "1e+", ".5", "+1", "(", ")", "*", "\x0c", "]", "}", "[]]", "{}}"} {
+		l := NewLexer([]byte(input))
+		var err error
+		for err == nil {
+			_, err = l.Scan()
+		}
+		if err == io.EOF {
+			t.Fatalf("%q: expected error", input)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package lexer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// SkipValue will skip the next value of the input, returning the exact raw
+// bytes of the value, without any surrounding whitespace. Once skipped, Next
+// returns the token following the value.
+func (l *Lexer) SkipValue() ([]byte, error) {
+	if l.lenient {
+		t, err := l.Scan()
+		if err == io.EOF {
+			return nil, token.Error(token.ErrInvalidJSON, "unexpected end of input, expected value")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return l.skipTokens(l.start, t)
+	}
+	start := skipSpaces(l.input, l.index+1)
+	if start >= len(l.input) {
+		return nil, token.Error(token.ErrInvalidJSON, "unexpected end of input, expected value")
+	}
+	return l.skipFrom(start)
+}
+
+// SkipCurrent will skip the rest of the value, which begins with the token
+// most recently returned by Next or Scan, returning the exact raw bytes of
+// the value. Once skipped, Next returns the token following the value.
+func (l *Lexer) SkipCurrent() ([]byte, error) {
+	if l.start >= len(l.input) {
+		return nil, token.Error(token.ErrInvalidJSON, "unexpected end of input, expected value")
+	}
+	if l.lenient {
+		return l.skipTokens(l.start, l.last)
+	}
+	if b := l.input[l.start]; b == '{' || b == '[' {
+		// the opening brace was pushed by Scan, but is closed by skipFrom
+		l.stack.Pop()
+	}
+	return l.skipFrom(l.start)
+}
+
+// skipFrom will skip the value starting at the given offset, leaving the
+// value as the most recent token
+func (l *Lexer) skipFrom(start int) ([]byte, error) {
+	if b := l.input[start]; b == '{' || b == '[' {
+		// large inputs are indexed, so containers are skipped without
+		// looking at their contents
+		if closing, ok := l.closing(start); ok {
+			return l.skipped(start, closing+1), nil
+		}
+	}
+	end, err := l.skip(start)
+	if err != nil {
+		return nil, err
+	}
+	return l.skipped(start, end), nil
+}
+
+// skipped leaves the value between the given offsets as the most recent
+// token, returning its raw bytes
+func (l *Lexer) skipped(start, end int) []byte {
+	l.start, l.index = start, end-1
+	l.valueStart, l.valueEnd = start, end
+	return l.input[start:end]
+}
+
+// skip returns the offset immediately following the value, which starts at
+// the given offset. The value is validated as a whole, so it is never
+// returned, unless it is valid JSON. Nested containers are tracked using a
+// local stack, so deeply nested values do not recurse.
+func (l *Lexer) skip(i int) (int, error) {
+	var (
+		braces [32]byte
+		err    error
+	)
+	stack := braces[:0]
+	for {
+		if i = skipSpaces(l.input, i); i >= len(l.input) {
+			return 0, token.Error(token.ErrInvalidJSON, "unexpected end of input, expected value")
+		}
+		if b := l.input[i]; b == '{' || b == '[' {
+			stack = append(stack, b)
+			if i = skipSpaces(l.input, i+1); i >= len(l.input) || l.input[i] != closer(b) {
+				if b == '{' {
+					if i, err = skipKey(l.input, i); err != nil {
+						return 0, err
+					}
+				}
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			i++
+		} else if i, err = skipScalar(l.input, i); err != nil {
+			return 0, err
+		}
+		// the value has ended, so close every container, which ends after it,
+		// until the next value of a container is found
+		for {
+			if len(stack) == 0 {
+				return i, nil
+			}
+			if i = skipSpaces(l.input, i); i >= len(l.input) {
+				return 0, token.Error(token.ErrMissingBrace, string(stack[len(stack)-1]))
+			}
+			top := stack[len(stack)-1]
+			if b := l.input[i]; b == ',' {
+				if next := skipSpaces(l.input, i+1); next < len(l.input) && l.input[next] == closer(top) {
+					return 0, token.Error(token.ErrInvalidJSON, "trailing comma")
+				}
+				if top == '{' {
+					if i, err = skipKey(l.input, i+1); err != nil {
+						return 0, err
+					}
+				} else {
+					i++
+				}
+				break
+			} else if b != closer(top) {
+				if b == '}' || b == ']' {
+					return 0, token.Error(token.ErrUnmatchedBrace, fmt.Sprintf("%q at offset %d", b, i))
+				}
+				return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected character %q at offset %d, expected comma or %q", b, i, closer(top)))
+			}
+			stack = stack[:len(stack)-1]
+			i++
+		}
+	}
+}
+
+// closer returns the closing brace or bracket of the given opening brace or
+// bracket
+func closer(b byte) byte {
+	if b == '{' {
+		return '}'
+	}
+	return ']'
+}
+
+// skipKey returns the offset immediately following the colon of the object
+// member, which starts at the given offset
+func skipKey(data []byte, i int) (int, error) {
+	if i = skipSpaces(data, i); i >= len(data) || data[i] != '"' {
+		return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object field at offset %d", i))
+	}
+	i, err := skipString(data, i)
+	if err != nil {
+		return 0, err
+	}
+	if i = skipSpaces(data, i); i >= len(data) || data[i] != ':' {
+		return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected colon at offset %d", i))
+	}
+	return i + 1, nil
+}
+
+// skipScalar returns the offset immediately following the string, number or
+// literal, which starts at the given offset
+func skipScalar(data []byte, i int) (int, error) {
+	switch b := data[i]; b {
+	case '"':
+		return skipString(data, i)
+	case 't':
+		return skipLiteral(data, i, TRUE)
+	case 'f':
+		return skipLiteral(data, i, FALSE)
+	case 'n':
+		return skipLiteral(data, i, NULL)
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		end, t := number(data, i)
+		if t == token.Unknown {
+			return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid number at offset %d", i))
+		}
+		return end, nil
+	default:
+		return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected character %q at offset %d", b, i))
+	}
+}
+
+func skipString(data []byte, i int) (int, error) {
+	end := closingQuote(data, i+1)
+	if end >= len(data) {
+		return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unterminated string at offset %d", i))
+	}
+	if _, err := validString(data[i+1:end], false); err != nil {
+		return 0, err
+	}
+	return end + 1, nil
+}
+
+func skipLiteral(data []byte, i int, lit []byte) (int, error) {
+	if !literal(data, i, lit) {
+		return 0, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid literal at offset %d, expected %s", i, lit))
+	}
+	return i + len(lit), nil
+}
@@ -0,0 +1,169 @@
+package lexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+func TestSkipValue(t *testing.T) {
+	tt := []struct {
+		input string
+		value string
+		next  string
+		err   error
+	}{
+		{`"a"`, `"a"`, "", nil},
+		{` "}" ,`, `"}"`, ",", nil},
+		{`"a\"b\\" ,`, `"a\"b\\"`, ",", nil},
+		{`{"a":"}"}`, `{"a":"}"}`, "", nil},
+		{`{"a": ["]", {"b": "\\"}]} :`, `{"a": ["]", {"b": "\\"}]}`, ":", nil},
+		{`[[1, 2], [3]],`, `[[1, 2], [3]]`, ",", nil},
+		{`[{"a": [{}]}],`, `[{"a": [{}]}]`, ",", nil},
+		{"\t\r\n-12.5e+3,", "-12.5e+3", ",", nil},
+		{`3,`, `3`, ",", nil},
+		{`0`, `0`, "", nil},
+		{`true,`, `true`, ",", nil},
+		{`false:`, `false`, ":", nil},
+		{`null`, `null`, "", nil},
+		{``, "", "", token.ErrInvalidJSON},
+		{`   `, "", "", token.ErrInvalidJSON},
+		{`"abc`, "", "", token.ErrInvalidJSON},
+		{`"abc\"`, "", "", token.ErrInvalidJSON},
+		{`{"a": 1`, "", "", token.ErrMissingBrace},
+		{`{"a": "}"`, "", "", token.ErrMissingBrace},
+		{`{"a": [1}`, "", "", token.ErrUnmatchedBrace},
+		{`[1, 2}`, "", "", token.ErrUnmatchedBrace},
+		{`{"a": "b]`, "", "", token.ErrInvalidJSON},
+		{`tru`, "", "", token.ErrInvalidJSON},
+		{`nul,`, "", "", token.ErrInvalidJSON},
+		{`fals3`, "", "", token.ErrInvalidJSON},
+		{`}`, "", "", token.ErrInvalidJSON},
+		{`:1`, "", "", token.ErrInvalidJSON},
+	}
+	defer func(threshold int) { indexThreshold = threshold }(indexThreshold)
+	for _, threshold := range []int{indexThreshold, 0} {
+		indexThreshold = threshold
+		for _, tc := range tt {
+			t.Run(tc.input, func(t *testing.T) {
+				testSkipValue(t, tc.input, tc.value, tc.next, tc.err)
+			})
+		}
+	}
+}
+
+func testSkipValue(t *testing.T, input, expected, expectedNext string, expectedErr error) {
+	l := NewLexer([]byte(input))
+	value, err := l.SkipValue()
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected error: %v, got: %v", expectedErr, err)
+	}
+	if err != nil {
+		return
+	}
+	if string(value) != expected {
+		t.Fatalf("expected %s, got %s", expected, value)
+	}
+	next, err := l.Next()
+	if expectedNext == "" {
+		if err != io.EOF {
+			t.Fatalf("expected EOF, got: %v %v", next, err)
+		}
+	} else if err != nil || next.ToString() != expectedNext {
+		t.Fatalf("expected %s, got: %v %v", expectedNext, next, err)
+	}
+}
+
+func TestSkipIndexed(t *testing.T) {
+	defer func(threshold int) { indexThreshold = threshold }(indexThreshold)
+	indexThreshold = 0
+
+	// the index matches the braces, but the contents must still be valid
+	for _, input := range []string{`{"a": [1,, 2]}`, `[{"a" 1}]`, `[1] [tru]`, "[\"\x01\"]"} {
+		l := NewLexer([]byte(input))
+		if _, err := l.SkipValue(); err == nil && l.structure != nil {
+			t.Fatalf("%s: invalid input indexed", input)
+		}
+	}
+	l := NewLexer([]byte(`{"a": [1, 2]} [{"b": "]"}]`))
+	if _, err := l.SkipValue(); err != nil || l.structure == nil {
+		t.Fatalf("expected input to be indexed: %v", err)
+	}
+	value, err := l.SkipValue()
+	if err != nil || string(value) != `[{"b": "]"}]` {
+		t.Fatalf("unexpected value: %s %v", value, err)
+	}
+}
+
+func TestSkipCurrent(t *testing.T) {
+	l := NewLexer([]byte(`{"a": {"b": "}"}, "c": [1, "]"], "d": 12, "e": "f", "g": [{"h": -1}], "i": null}`))
+	var skipped []string
+	for {
+		tk, err := l.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tk.Type == token.Colon {
+			if _, err := l.Next(); err != nil {
+				t.Fatal(err)
+			}
+			value, err := l.SkipCurrent()
+			if err != nil {
+				t.Fatal(err)
+			}
+			skipped = append(skipped, string(value))
+		}
+	}
+	expected := []string{`{"b": "}"}`, `[1, "]"]`, `12`, `"f"`, `[{"h": -1}]`, `null`}
+	if len(skipped) != len(expected) {
+		t.Fatal(skipped)
+	}
+	for i := range expected {
+		if skipped[i] != expected[i] {
+			t.Fatalf("expected %s, got %s", expected[i], skipped[i])
+		}
+	}
+}
+
+func FuzzSkipValue(f *testing.F) {
+	for _, seed := range []string{`{"a":"}"}`, `[1, "\\", {"b": [true, null]}]`, `-1.5e3`, `"\"}"`, `{"a": [}`, `tru`, ` false `} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		l := NewLexer(data)
+		value, err := l.SkipValue()
+		if !json.Valid(data) {
+			// invalid documents may still contain a skippable value, but
+			// skipping must never panic
+			return
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", data, err)
+		}
+		if !bytes.Equal(value, bytes.TrimSpace(data)) {
+			t.Fatalf("skipped %q, expected %q", value, bytes.TrimSpace(data))
+		}
+		if next, err := l.Next(); err != io.EOF {
+			t.Fatalf("expected EOF, got: %v %v", next, err)
+		}
+
+		// the same value within an array, followed by another element
+		l = NewLexer(append(append([]byte("["), data...), ", 1]"...))
+		if _, err := l.Next(); err != nil {
+			t.Fatal(err)
+		}
+		if value, err = l.SkipValue(); err != nil || !bytes.Equal(value, bytes.TrimSpace(data)) {
+			t.Fatalf("skipped %q, expected %q: %v", value, bytes.TrimSpace(data), err)
+		}
+		if next, err := l.Next(); err != nil || next.Type != token.Comma {
+			t.Fatalf("expected comma, got: %v %v", next, err)
+		}
+	})
+}
@@ -1,7 +1,5 @@
 package lexer
 
-import "fmt"
-
 type Stack struct {
 	index int
 	stack []byte
@@ -17,12 +15,13 @@ func (s *Stack) IsEmpty() bool {
 	return s.index == 0
 }
 
+// Pop removes and returns the top of the stack, or zero if the stack is
+// empty
 func (s *Stack) Pop() byte {
-	s.index--
-	if s.index < 0 {
-		fmt.Println("stack underflow")
+	if s.index <= 0 {
 		return 0
 	}
+	s.index--
 	return s.stack[s.index+1]
 }
 
@@ -34,3 +33,11 @@ func (s *Stack) Push(b byte) {
 		s.stack[s.index] = b
 	}
 }
+
+// Peek returns the top of the stack, or zero if the stack is empty
+func (s *Stack) Peek() byte {
+	if s.index <= 0 {
+		return 0
+	}
+	return s.stack[s.index]
+}
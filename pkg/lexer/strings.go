@@ -0,0 +1,139 @@
+package lexer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// controls returns a word, in which the high bit of each byte is set, if the
+// corresponding byte of w is a control character (less than 0x20), which
+// must be escaped within strings
+func controls(w uint64) uint64 {
+	return ^((w & lows) + ones*(0x80-0x20)) & ^w & highs
+}
+
+// indexControlOrEscape returns the offset of the first control character or
+// backslash of data, from offset i, or len(data) if there is none
+func indexControlOrEscape(data []byte, i int) int {
+	for ; i+8 <= len(data); i += 8 {
+		w := binary.LittleEndian.Uint64(data[i:])
+		if m := controls(w) | equal(w, '\\'); m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(data) && data[i] >= 0x20 && data[i] != '\\'; i++ {
+	}
+	return i
+}
+
+// validString checks the raw contents of a string, without the quotes,
+// returning whether the string contains any escape sequences, which must be
+// unescaped. Control characters and invalid escape sequences are rejected.
+// Invalid UTF-8 is accepted, and left as is. In the lenient mode, \' is a
+// valid escape sequence as well.
+func validString(data []byte, lenient bool) (bool, error) {
+	var escaped bool
+	for i := indexControlOrEscape(data, 0); i < len(data); i = indexControlOrEscape(data, i) {
+		if data[i] != '\\' {
+			return false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unescaped control character %q in string", data[i]))
+		}
+		escaped = true
+		if i+1 >= len(data) {
+			return false, token.Error(token.ErrInvalidJSON, "unterminated escape sequence in string")
+		}
+		switch data[i+1] {
+		case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			i += 2
+		case '\'':
+			if !lenient {
+				return false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid escape sequence in string: %q", data[i:i+2]))
+			}
+			i += 2
+		case 'u':
+			if _, ok := hex4(data[i+2:]); !ok {
+				return false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid unicode escape sequence in string: %q", data[i:min(i+6, len(data))]))
+			}
+			i += 6
+		default:
+			return false, token.Error(token.ErrInvalidJSON, fmt.Sprintf("invalid escape sequence in string: %q", data[i:i+2]))
+		}
+	}
+	return escaped, nil
+}
+
+// hex4 returns the value of the four hexadecimal digits at the start of data
+func hex4(data []byte) (rune, bool) {
+	if len(data) < 4 {
+		return 0, false
+	}
+	var r rune
+	for _, b := range data[:4] {
+		switch {
+		case b >= '0' && b <= '9':
+			b -= '0'
+		case b >= 'a' && b <= 'f':
+			b -= 'a' - 10
+		case b >= 'A' && b <= 'F':
+			b -= 'A' - 10
+		default:
+			return 0, false
+		}
+		r = r<<4 | rune(b)
+	}
+	return r, true
+}
+
+// unescape returns the value of the raw contents of a valid string, which
+// contains escape sequences. As with the standard library, surrogates which
+// are not part of a valid pair are replaced by utf8.RuneError.
+func unescape(data []byte) []byte {
+	s := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		if data[i] != '\\' {
+			j := i + 1
+			for j < len(data) && data[j] != '\\' {
+				j++
+			}
+			s = append(s, data[i:j]...)
+			i = j
+			continue
+		}
+		switch c := data[i+1]; c {
+		case 'b':
+			s = append(s, '\b')
+		case 'f':
+			s = append(s, '\f')
+		case 'n':
+			s = append(s, '\n')
+		case 'r':
+			s = append(s, '\r')
+		case 't':
+			s = append(s, '\t')
+		case 'u':
+			r, _ := hex4(data[i+2:])
+			i += 6
+			if utf16.IsSurrogate(r) {
+				high := r
+				r = utf8.RuneError
+				if i+6 <= len(data) && data[i] == '\\' && data[i+1] == 'u' {
+					low, _ := hex4(data[i+2:])
+					if pair := utf16.DecodeRune(high, low); pair != utf8.RuneError {
+						r = pair
+						i += 6
+					}
+				}
+			}
+			s = utf8.AppendRune(s, r)
+			continue
+		default:
+			s = append(s, c)
+		}
+		i += 2
+	}
+	return s
+}
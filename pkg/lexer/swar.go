@@ -0,0 +1,96 @@
+package lexer
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The functions of this file scan the input a word (8 bytes) at a time,
+// using SWAR (SIMD within a register) techniques, and fall back to scanning
+// a byte at a time for the last bytes of the input.
+
+const (
+	ones  = 0x0101010101010101
+	highs = 0x8080808080808080
+	lows  = 0x7f7f7f7f7f7f7f7f
+)
+
+// zeros returns a word, in which the high bit of each byte is set, if the
+// corresponding byte of w is zero. Unlike the common (w - ones) & ^w & highs
+// trick, the result is exact for every byte, and not only the lowest.
+func zeros(w uint64) uint64 {
+	return ^(((w & lows) + lows) | w | lows)
+}
+
+// equal returns a word, in which the high bit of each byte is set, if the
+// corresponding byte of w is equal to b
+func equal(w uint64, b byte) uint64 {
+	return zeros(w ^ (ones * uint64(b)))
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// skipSpaces returns the offset of the first byte of data, from offset i,
+// which is not whitespace, or len(data) if there is none
+func skipSpaces(data []byte, i int) int {
+	for ; i+8 <= len(data); i += 8 {
+		w := binary.LittleEndian.Uint64(data[i:])
+		spaces := equal(w, ' ') | equal(w, '\t') | equal(w, '\n') | equal(w, '\r')
+		if spaces != highs {
+			return i + bits.TrailingZeros64(^spaces&highs)/8
+		}
+	}
+	for ; i < len(data) && isSpace(data[i]); i++ {
+	}
+	return i
+}
+
+// indexQuoteOrEscape returns the offset of the first quote or backslash of
+// data, from offset i, or len(data) if there is none
+func indexQuoteOrEscape(data []byte, i int) int {
+	for ; i+8 <= len(data); i += 8 {
+		w := binary.LittleEndian.Uint64(data[i:])
+		if m := equal(w, '"') | equal(w, '\\'); m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(data) && data[i] != '"' && data[i] != '\\'; i++ {
+	}
+	return i
+}
+
+// indexStructural returns the offset of the first quote, brace or bracket of
+// data, from offset i, or len(data) if there is none
+func indexStructural(data []byte, i int) int {
+	for ; i+8 <= len(data); i += 8 {
+		w := binary.LittleEndian.Uint64(data[i:])
+		m := equal(w, '"') | equal(w, '{') | equal(w, '}') | equal(w, '[') | equal(w, ']')
+		if m != 0 {
+			return i + bits.TrailingZeros64(m)/8
+		}
+	}
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '"', '{', '}', '[', ']':
+			return i
+		}
+	}
+	return i
+}
+
+// closingQuote returns the offset of the quote ending the string, which
+// begins at offset i, skipping any escaped characters. If the string is not
+// terminated, len(data) is returned.
+func closingQuote(data []byte, i int) int {
+	for {
+		if i = indexQuoteOrEscape(data, i); i >= len(data) {
+			return len(data)
+		}
+		if data[i] == '"' {
+			return i
+		}
+		i += 2
+	}
+}
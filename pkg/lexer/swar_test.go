@@ -0,0 +1,313 @@
+package lexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+// The simple scanners are the byte at a time equivalents of the SWAR
+// scanners, which the SWAR scanners are tested against.
+
+func simpleSkipSpaces(data []byte, i int) int {
+	for ; i < len(data) && isSpace(data[i]); i++ {
+	}
+	return i
+}
+
+func simpleIndexQuoteOrEscape(data []byte, i int) int {
+	for ; i < len(data); i++ {
+		if data[i] == '"' || data[i] == '\\' {
+			return i
+		}
+	}
+	return len(data)
+}
+
+func simpleIndexStructural(data []byte, i int) int {
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '"', '{', '}', '[', ']':
+			return i
+		}
+	}
+	return len(data)
+}
+
+func simpleClosingQuote(data []byte, i int) int {
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(data)
+}
+
+// simpleIndex returns the matching closing offsets of the given input, or
+// false if the input cannot be indexed
+func simpleIndex(data []byte) (map[int]int, bool) {
+	matches := map[int]int{}
+	var stack []int
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			if i = simpleClosingQuote(data, i+1); i == len(data) {
+				return nil, false
+			}
+		case '{', '[':
+			stack = append(stack, i)
+		case '}', ']':
+			if len(stack) == 0 {
+				return nil, false
+			}
+			open := stack[len(stack)-1]
+			if (data[open] == '{') != (data[i] == '}') {
+				return nil, false
+			}
+			matches[open] = i
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return matches, len(stack) == 0
+}
+
+func TestSWAR(t *testing.T) {
+	tt := []struct {
+		input      string
+		spaces     int
+		escape     int
+		structural int
+		quote      int
+	}{
+		{"", 0, 0, 0, 0},
+		{" \t\r\n", 4, 4, 4, 4},
+		{" \t\r\n \t\r\n \t\r\nx", 12, 13, 13, 13},
+		{`abcdefghijklmnop"`, 0, 16, 16, 16},
+		{`abcdefghij\"klmn"`, 0, 10, 11, 16},
+		{`abcdefgh\\"`, 0, 8, 10, 10},
+		{`        {"a": [1]}`, 8, 9, 8, 9},
+		{"\x80\xff\x00 \x7f\"", 0, 5, 5, 5},
+	}
+	for _, tc := range tt {
+		data := []byte(tc.input)
+		if i := skipSpaces(data, 0); i != tc.spaces {
+			t.Errorf("skipSpaces(%q) = %d, expected %d", tc.input, i, tc.spaces)
+		}
+		if i := indexQuoteOrEscape(data, 0); i != tc.escape {
+			t.Errorf("indexQuoteOrEscape(%q) = %d, expected %d", tc.input, i, tc.escape)
+		}
+		if i := indexStructural(data, 0); i != tc.structural {
+			t.Errorf("indexStructural(%q) = %d, expected %d", tc.input, i, tc.structural)
+		}
+		if i := closingQuote(data, 0); i != tc.quote {
+			t.Errorf("closingQuote(%q) = %d, expected %d", tc.input, i, tc.quote)
+		}
+	}
+}
+
+func FuzzSWAR(f *testing.F) {
+	for _, seed := range []string{"", "        x", ` "\\\"" `, "\t\r\n {}[]\"\\ abcdefghijkl", "\x00\x80\xfe\xff"} {
+		f.Add([]byte(seed), 0)
+	}
+	f.Fuzz(func(t *testing.T, data []byte, i int) {
+		if i < 0 || i > len(data) {
+			return
+		}
+		if a, b := skipSpaces(data, i), simpleSkipSpaces(data, i); a != b {
+			t.Fatalf("skipSpaces(%q, %d) = %d, expected %d", data, i, a, b)
+		}
+		if a, b := indexQuoteOrEscape(data, i), simpleIndexQuoteOrEscape(data, i); a != b {
+			t.Fatalf("indexQuoteOrEscape(%q, %d) = %d, expected %d", data, i, a, b)
+		}
+		if a, b := indexStructural(data, i), simpleIndexStructural(data, i); a != b {
+			t.Fatalf("indexStructural(%q, %d) = %d, expected %d", data, i, a, b)
+		}
+		if a, b := closingQuote(data, i), simpleClosingQuote(data, i); a != b {
+			t.Fatalf("closingQuote(%q, %d) = %d, expected %d", data, i, a, b)
+		}
+	})
+}
+
+func FuzzIndex(f *testing.F) {
+	for _, seed := range []string{`{}`, `[{"a": "}"}, [1, "\"]"]]`, `{"a": [}`, `["\\"]`, `"`, `]`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		idx, ok := NewIndex(data)
+		matches, expected := simpleIndex(data)
+		if ok != expected {
+			t.Fatalf("NewIndex(%q) = %v, expected %v", data, ok, expected)
+		}
+		if !ok {
+			return
+		}
+		for open, close := range matches {
+			if i, ok := idx.Closing(open); !ok || i != close {
+				t.Fatalf("Closing(%d) of %q = %d, expected %d", open, data, i, close)
+			}
+		}
+		if len(idx.opening) != len(matches) {
+			t.Fatalf("%d containers indexed, expected %d", len(idx.opening), len(matches))
+		}
+	})
+}
+
+// skipTokens skips the container, which begins with the current token, by
+// lexing every token of the container
+func skipTokens(l *Lexer) error {
+	depth := 1
+	for depth > 0 {
+		t, err := l.Scan()
+		if err != nil {
+			return err
+		}
+		if t.IsOpening() {
+			depth++
+		} else if t.IsEnding() {
+			depth--
+		}
+	}
+	return nil
+}
+
+func FuzzSkipContainer(f *testing.F) {
+	for _, seed := range []string{`{"a": {"b": "}"}, "c": 1}`, `[[1, 2], {"a": "\\"}]`, `[" ]\" "]`} {
+		f.Add([]byte(seed))
+	}
+	defer func(threshold int) { indexThreshold = threshold }(indexThreshold)
+	indexThreshold = 0
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if !json.Valid(data) {
+			return
+		}
+		indexed, simple := NewLexer(data), NewLexer(data)
+		a, _ := indexed.Scan()
+		simple.Scan()
+		if !a.IsOpening() {
+			return
+		}
+		if !indexed.SkipContainer() {
+			t.Fatalf("%q not indexed", data)
+		}
+		if err := skipTokens(simple); err != nil {
+			t.Fatal(err)
+		}
+		if indexed.index != simple.index {
+			t.Fatalf("skipped to %d, expected %d", indexed.index, simple.index)
+		}
+		if _, err := indexed.Scan(); err != io.EOF {
+			t.Fatalf("expected EOF, got: %v", err)
+		}
+	})
+}
+
+// large returns a multi-megabyte document, of indented objects with long
+// strings, similar to a pretty printed export
+func large() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("[\n")
+	for i := 0; i < 20000; i++ {
+		if i > 0 {
+			buf.WriteString(",\n")
+		}
+		fmt.Fprintf(&buf, "\t{\n\t\t\"id\": %d,\n\t\t\"name\": \"customer number %d\",\n", i, i)
+		fmt.Fprintf(&buf, "\t\t\"description\": \"%s \\\"quoted\\\" %s\",\n", bytes.Repeat([]byte("lorem ipsum "), 10), "{[}]")
+		buf.WriteString("\t\t\"tags\": [\"a\", \"b\", \"c\"],\n\t\t\"active\": true\r\n\t}")
+	}
+	buf.WriteString("\n]")
+	return buf.Bytes()
+}
+
+func BenchmarkScanLarge(b *testing.B) {
+	data := large()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := NewLexer(data)
+		for {
+			if _, err := l.Scan(); err != nil {
+				if err != io.EOF {
+					b.Fatal(err)
+				}
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkSkipLarge(b *testing.B) {
+	data := large()
+	b.Run("tokens", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			l := NewLexer(data)
+			l.Scan()
+			if err := skipTokens(l); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("skip", func(b *testing.B) {
+		defer func(threshold int) { indexThreshold = threshold }(indexThreshold)
+		indexThreshold = len(data) + 1
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			l := NewLexer(data)
+			l.Scan()
+			if _, err := l.SkipCurrent(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			l := NewLexer(data)
+			l.Scan()
+			if !l.SkipContainer() {
+				b.Fatal("not indexed")
+			}
+		}
+	})
+	b.Run("std", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			var v json.RawMessage
+			if err := json.Unmarshal(data, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestScanLarge(t *testing.T) {
+	data := large()
+	if !json.Valid(data) {
+		t.Fatal("invalid document")
+	}
+	l := NewLexer(data)
+	var count int
+	for {
+		tt, err := l.Scan()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt == token.String {
+			count++
+		}
+	}
+	// five keys, the name and description, and the three tags
+	if count != 20000*10 {
+		t.Fatal(count)
+	}
+}
@@ -0,0 +1,211 @@
+package patch
+
+import (
+	"fmt"
+)
+
+// The functions in this file operate on documents decoded without any type
+// information, meaning that objects are map[string]interface{}, arrays are
+// []interface{} and null is nil.
+
+// get will return the value referenced by the given pointer
+func get(doc interface{}, ptr Pointer) (interface{}, error) {
+	for _, t := range ptr {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			v, ok := node[t]
+			if !ok {
+				return nil, Error(ErrPathNotFound, ptr.String())
+			}
+			doc = v
+		case []interface{}:
+			i, err := index(t, len(node))
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, Error(ErrPathNotFound, ptr.String())
+		}
+	}
+	return doc, nil
+}
+
+// mutate will invoke fn with the container of the value referenced by the
+// given pointer, and the final reference token. The container returned by
+// fn replaces the original container, as slices may be reallocated.
+func mutate(doc interface{}, ptr Pointer, fn func(container interface{}, t string) (interface{}, error)) (interface{}, error) {
+	if len(ptr) == 1 {
+		return fn(doc, ptr[0])
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[ptr[0]]
+		if !ok {
+			return nil, Error(ErrPathNotFound, ptr.String())
+		}
+		child, err := mutate(child, ptr[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[ptr[0]] = child
+		return node, nil
+	case []interface{}:
+		i, err := index(ptr[0], len(node))
+		if err != nil {
+			return nil, err
+		}
+		child, err := mutate(node[i], ptr[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = child
+		return node, nil
+	}
+	return nil, Error(ErrPathNotFound, ptr.String())
+}
+
+func add(doc interface{}, ptr Pointer, value interface{}) (interface{}, error) {
+	if len(ptr) == 0 {
+		return value, nil
+	}
+	return mutate(doc, ptr, func(container interface{}, t string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			node[t] = value
+			return node, nil
+		case []interface{}:
+			if t == "-" {
+				return append(node, value), nil
+			}
+			i, err := index(t, len(node)+1)
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, Error(ErrPathNotFound, ptr.String())
+	})
+}
+
+func remove(doc interface{}, ptr Pointer) (interface{}, error) {
+	if len(ptr) == 0 {
+		return nil, nil
+	}
+	return mutate(doc, ptr, func(container interface{}, t string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			if _, ok := node[t]; !ok {
+				return nil, Error(ErrPathNotFound, ptr.String())
+			}
+			delete(node, t)
+			return node, nil
+		case []interface{}:
+			i, err := index(t, len(node))
+			if err != nil {
+				return nil, err
+			}
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, Error(ErrPathNotFound, ptr.String())
+	})
+}
+
+func replace(doc interface{}, ptr Pointer, value interface{}) (interface{}, error) {
+	if len(ptr) == 0 {
+		return value, nil
+	}
+	return mutate(doc, ptr, func(container interface{}, t string) (interface{}, error) {
+		switch node := container.(type) {
+		case map[string]interface{}:
+			if _, ok := node[t]; !ok {
+				return nil, Error(ErrPathNotFound, ptr.String())
+			}
+			node[t] = value
+			return node, nil
+		case []interface{}:
+			i, err := index(t, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node[i] = value
+			return node, nil
+		}
+		return nil, Error(ErrPathNotFound, ptr.String())
+	})
+}
+
+// deepCopy will return a copy of the given value, which shares no objects
+// or arrays with the original.
+func deepCopy(v interface{}) interface{} {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(node))
+		for k, v := range node {
+			m[k] = deepCopy(v)
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, len(node))
+		for i, v := range node {
+			arr[i] = deepCopy(v)
+		}
+		return arr
+	}
+	return v
+}
+
+// equal reports whether the two values are equal, as defined by the RFC 6902
+// test operation. Numbers are compared by their numeric value.
+func equal(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if n, ok := number(a); ok {
+		m, ok := number(b)
+		return ok && n == m
+	}
+	return a == b
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func describe(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,32 @@
+package patch
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidPatch   = errors.New("invalid patch")
+	ErrInvalidPointer = errors.New("invalid json pointer")
+	ErrInvalidTarget  = errors.New("patch target must be a non-nil pointer")
+	ErrPathNotFound   = errors.New("path not found")
+	ErrTestFailed     = errors.New("test operation failed")
+)
+
+type patchErr struct {
+	err     error
+	details string
+}
+
+// Error will return the given error, wrapped with the given details
+func Error(err error, details string) error {
+	return patchErr{err, details}
+}
+
+func (err patchErr) Error() string {
+	return fmt.Sprintf("%v: %v", err.err, err.details)
+}
+
+func (err patchErr) Unwrap() error {
+	return err.err
+}
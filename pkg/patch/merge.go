@@ -0,0 +1,100 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+// Merge will apply the given RFC 7386 merge patch to the given JSON
+// document, returning the patched document.
+func Merge(doc, patch []byte) ([]byte, error) {
+	var target, p interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// MergeInto will apply the given RFC 7386 merge patch to the value pointed
+// to by v. The members of the patch are checked against the struct tags of
+// the type of v, and the patched document is decoded with all required
+// checks. The value is only modified if the patch is applied successfully.
+func MergeInto(v interface{}, patch []byte) error {
+	vo := reflect.ValueOf(v)
+	if vo.Kind() != reflect.Ptr || vo.IsNil() {
+		return ErrInvalidTarget
+	}
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return err
+	}
+	if err := checkMerge(vo.Type().Elem(), p, Pointer{}); err != nil {
+		return err
+	}
+	return into(vo, func(doc []byte) ([]byte, error) {
+		return Merge(doc, patch)
+	})
+}
+
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{})
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = merge(t[k], v)
+		}
+	}
+	return t
+}
+
+// checkMerge will ensure that every member of the given merge patch refers
+// to a field known to the given type.
+func checkMerge(t reflect.Type, patch interface{}, ptr Pointer) error {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		tags, err := structtag.FromValue(reflect.New(t).Elem())
+		if err != nil {
+			return err
+		}
+		if tags.UnmarshalInterface {
+			return nil
+		}
+		for name, v := range p {
+			tag, ok := tags.Tags[name]
+			if !ok {
+				return Error(ErrPathNotFound, fmt.Sprintf("%s: no field %q on %v", append(ptr, name), name, t))
+			}
+			if err := checkMerge(t.Field(tag.FieldIndex).Type, v, append(ptr, name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for name, v := range p {
+			if err := checkMerge(t.Elem(), v, append(ptr, name)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+// Test cases are taken from RFC 7386, Appendix A
+func TestMerge(t *testing.T) {
+	tt := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.patch, func(t *testing.T) {
+			result, err := Merge([]byte(tf.doc), []byte(tf.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSON(t, result, tf.expected)
+		})
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	c := customer()
+	if err := MergeInto(&c, []byte(`{"email": "basse@jakobsen.dev", "address": {"country": "Denmark"}, "tags": null}`)); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "lasse" || c.Email != "basse@jakobsen.dev" || c.Tags != nil ||
+		c.Address.Country != "Denmark" || c.Address.Street != "Somewhere 1" {
+		t.Fatalf("%+v", c)
+	}
+}
+
+func TestMergeIntoEnforcesRequired(t *testing.T) {
+	tt := []struct {
+		name  string
+		patch string
+		check func(err error) bool
+	}{
+		{"remove required field", `{"name": null}`, structtag.IsRequiredErr},
+		{"remove nested required field", `{"address": {"street": null}}`, structtag.IsRequiredErr},
+		{"invalid value", `{"email": "nope"}`, func(err error) bool {
+			return errors.Is(err, errInvalidEmail)
+		}},
+		{"unknown field", `{"address": {"zip": 1234}}`, func(err error) bool {
+			return errors.Is(err, ErrPathNotFound)
+		}},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			c := customer()
+			if err := MergeInto(&c, []byte(tf.patch)); !tf.check(err) {
+				t.Fatal("unexpected error:", err)
+			}
+			if c.Name != "lasse" || c.Email != "lasse@jakobsen.dev" || c.Address.Street != "Somewhere 1" {
+				t.Fatalf("value modified by failed patch: %+v", c)
+			}
+		})
+	}
+}
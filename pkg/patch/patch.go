@@ -0,0 +1,207 @@
+// Package patch implements RFC 6902 JSON Patch and RFC 7386 JSON Merge Patch.
+// Patches may be applied to raw JSON documents, or directly to Go values. When
+// applied to a Go value, the patched document is decoded using pkg/json,
+// meaning that `required` tags and the required.Required interface are
+// enforced on the result, and a patch can therefore never remove a required
+// field.
+package patch
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation is a single RFC 6902 operation. Value is given as a value
+// decoded without type information, such as by json.Unmarshal into an
+// interface{}.
+type Operation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// Patch is an RFC 6902 JSON Patch document, an ordered list of operations
+type Patch []Operation
+
+// Decode will parse the given JSON Patch document
+func Decode(data []byte) (Patch, error) {
+	var ops []map[string]interface{}
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+	patch := make(Patch, len(ops))
+	for i, op := range ops {
+		var ok bool
+		if patch[i].Op, ok = op["op"].(string); !ok {
+			return nil, Error(ErrInvalidPatch, fmt.Sprintf("operation %d: missing op", i))
+		}
+		if patch[i].Path, ok = op["path"].(string); !ok {
+			return nil, Error(ErrInvalidPatch, fmt.Sprintf("operation %d: missing path", i))
+		}
+		switch patch[i].Op {
+		case OpAdd, OpReplace, OpTest:
+			if patch[i].Value, ok = op["value"]; !ok {
+				return nil, Error(ErrInvalidPatch, fmt.Sprintf("operation %d: missing value", i))
+			}
+		case OpMove, OpCopy:
+			if patch[i].From, ok = op["from"].(string); !ok {
+				return nil, Error(ErrInvalidPatch, fmt.Sprintf("operation %d: missing from", i))
+			}
+		case OpRemove:
+		default:
+			return nil, Error(ErrInvalidPatch, fmt.Sprintf("operation %d: unknown op: %s", i, patch[i].Op))
+		}
+	}
+	return patch, nil
+}
+
+// Apply will apply the patch to the given JSON document, returning the
+// patched document. If any operation fails, no result is returned.
+func (patch Patch) Apply(doc []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	for i, op := range patch {
+		var err error
+		if v, err = op.apply(v); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// ApplyTo will apply the patch to the value pointed to by v. The paths of
+// the operations are checked against the struct tags of the type of v, and
+// the patched document is decoded with all required checks. The value is
+// only modified if the whole patch is applied successfully.
+func (patch Patch) ApplyTo(v interface{}) error {
+	vo := reflect.ValueOf(v)
+	if vo.Kind() != reflect.Ptr || vo.IsNil() {
+		return ErrInvalidTarget
+	}
+	for _, op := range patch {
+		paths := []string{op.Path}
+		if op.Op == OpMove || op.Op == OpCopy {
+			paths = append(paths, op.From)
+		}
+		for _, path := range paths {
+			ptr, err := ParsePointer(path)
+			if err != nil {
+				return err
+			}
+			if err := checkPath(vo.Type().Elem(), ptr); err != nil {
+				return err
+			}
+		}
+	}
+	return into(vo, patch.Apply)
+}
+
+func (op Operation) apply(doc interface{}) (interface{}, error) {
+	path, err := ParsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case OpAdd:
+		return add(doc, path, deepCopy(op.Value))
+	case OpRemove:
+		return remove(doc, path)
+	case OpReplace:
+		return replace(doc, path, deepCopy(op.Value))
+	case OpMove, OpCopy:
+		from, err := ParsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		v, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == OpCopy {
+			return add(doc, path, deepCopy(v))
+		}
+		if from.isPrefixOf(path) {
+			return nil, Error(ErrInvalidPatch, fmt.Sprintf("cannot move %s into itself", op.From))
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, v)
+	case OpTest:
+		v, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(v, op.Value) {
+			return nil, Error(ErrTestFailed, fmt.Sprintf("%s: %s != %s", op.Path, describe(v), describe(op.Value)))
+		}
+		return doc, nil
+	}
+	return nil, Error(ErrInvalidPatch, "unknown op: "+op.Op)
+}
+
+// into will marshal the value pointed to by vo, pass the document to fn and
+// decode the result into a new value of the same type. Only if this
+// succeeds, is the new value stored in vo.
+func into(vo reflect.Value, fn func([]byte) ([]byte, error)) error {
+	doc, err := json.Marshal(vo.Interface())
+	if err != nil {
+		return err
+	}
+	patched, err := fn(doc)
+	if err != nil {
+		return err
+	}
+	target := reflect.New(vo.Type().Elem())
+	if err := json.Unmarshal(patched, target.Interface()); err != nil {
+		return err
+	}
+	vo.Elem().Set(target.Elem())
+	return nil
+}
+
+// checkPath will ensure that the given pointer refers to a field which is
+// known to the given type. Without this check, patching an unknown field
+// would succeed, but be silently dropped when decoding the result.
+func checkPath(t reflect.Type, ptr Pointer) error {
+	for i, name := range ptr {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			tags, err := structtag.FromValue(reflect.New(t).Elem())
+			if err != nil {
+				return err
+			}
+			if tags.UnmarshalInterface {
+				return nil
+			}
+			tag, ok := tags.Tags[name]
+			if !ok {
+				return Error(ErrPathNotFound, fmt.Sprintf("%s: no field %q on %v", ptr[:i+1], name, t))
+			}
+			t = t.Field(tag.FieldIndex).Type
+		case reflect.Map, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
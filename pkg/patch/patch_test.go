@@ -0,0 +1,188 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+// assertJSON will compare the given documents by value, ignoring formatting
+// and the order of object members.
+func assertJSON(t *testing.T, result []byte, expected string) {
+	t.Helper()
+	var a, b interface{}
+	if err := json.Unmarshal(result, &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expected), &b); err != nil {
+		t.Fatal(err)
+	}
+	if !equal(a, b) {
+		t.Fatalf("%s != %s", result, expected)
+	}
+}
+
+// Test cases are taken from RFC 6902, Appendix A
+func TestApply(t *testing.T) {
+	tt := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+		err      error
+	}{
+		{"add object member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`, nil},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, nil},
+		{"remove object member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`, nil},
+		{"remove array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`, nil},
+		{"replace value", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`, nil},
+		{"move value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`, nil},
+		{"move array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`, nil},
+		{"copy value", `{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"}]`, `{"foo":{"bar":1},"baz":{"bar":1}}`, nil},
+		{"test success", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`, `{"baz":"qux","foo":["a",2,"c"]}`, nil},
+		{"test numbers by value", `{"n":1}`, `[{"op":"test","path":"/n","value":1.0}]`, `{"n":1}`, nil},
+		{"test error", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, ``, ErrTestFailed},
+		{"add nested member", `{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`, nil},
+		{"add to nonexistent target", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, ``, ErrPathNotFound},
+		{"add array value", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`, nil},
+		{"add null value", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"foo":"bar","baz":null}`, nil},
+		{"escaped pointer", `{"a/b":1,"m~n":2}`, `[{"op":"replace","path":"/a~1b","value":3},{"op":"remove","path":"/m~0n"}]`, `{"a/b":3}`, nil},
+		{"replace root", `{"foo":"bar"}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`, nil},
+		{"remove missing", `{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, ``, ErrPathNotFound},
+		{"index out of range", `[1,2]`, `[{"op":"add","path":"/3","value":3}]`, ``, ErrPathNotFound},
+		{"leading zero index", `[1,2]`, `[{"op":"replace","path":"/01","value":3}]`, ``, ErrInvalidPointer},
+		{"move into child", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`, ``, ErrInvalidPatch},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			patch, err := Decode([]byte(tf.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := patch.Apply([]byte(tf.doc))
+			if !errors.Is(err, tf.err) {
+				t.Fatalf("expected %v, received: %v", tf.err, err)
+			}
+			if tf.err == nil {
+				assertJSON(t, result, tf.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidPatch(t *testing.T) {
+	tt := []struct {
+		name  string
+		patch string
+	}{
+		{"missing op", `[{"path":"/a"}]`},
+		{"missing path", `[{"op":"remove"}]`},
+		{"missing value", `[{"op":"add","path":"/a"}]`},
+		{"missing from", `[{"op":"move","path":"/a"}]`},
+		{"unknown op", `[{"op":"delete","path":"/a"}]`},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tf.patch)); !errors.Is(err, ErrInvalidPatch) {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+type Address struct {
+	Street  string `json:"street,required"`
+	Country string `json:"country"`
+}
+
+type Customer struct {
+	Name    string   `json:"name,required"`
+	Email   Email    `json:"email"`
+	Tags    []string `json:"tags"`
+	Address *Address `json:"address"`
+}
+
+type Email string
+
+var errInvalidEmail = errors.New("invalid email")
+
+func (email Email) IsValueValid() error {
+	for i := range email {
+		if email[i] == '@' {
+			return nil
+		}
+	}
+	return errInvalidEmail
+}
+
+func customer() Customer {
+	return Customer{
+		Name:    "lasse",
+		Email:   "lasse@jakobsen.dev",
+		Tags:    []string{"big"},
+		Address: &Address{Street: "Somewhere 1"},
+	}
+}
+
+func TestApplyTo(t *testing.T) {
+	c := customer()
+	patch, err := Decode([]byte(`[
+		{"op": "replace", "path": "/name", "value": "basse"},
+		{"op": "add", "path": "/tags/-", "value": "customer"},
+		{"op": "add", "path": "/address/country", "value": "Denmark"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := patch.ApplyTo(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "basse" || len(c.Tags) != 2 || c.Tags[1] != "customer" ||
+		c.Address.Country != "Denmark" || c.Address.Street != "Somewhere 1" {
+		t.Fatalf("%+v", c)
+	}
+}
+
+func TestApplyToEnforcesRequired(t *testing.T) {
+	tt := []struct {
+		name  string
+		patch string
+		check func(err error) bool
+	}{
+		{"remove required field", `[{"op":"remove","path":"/name"}]`, structtag.IsRequiredErr},
+		{"null required field", `[{"op":"replace","path":"/name","value":null}]`, structtag.IsRequiredErr},
+		{"remove nested required field", `[{"op":"remove","path":"/address/street"}]`, structtag.IsRequiredErr},
+		{"invalid value", `[{"op":"replace","path":"/email","value":"nope"}]`, func(err error) bool {
+			return errors.Is(err, errInvalidEmail)
+		}},
+		{"unknown field", `[{"op":"add","path":"/phone","value":"1234"}]`, func(err error) bool {
+			return errors.Is(err, ErrPathNotFound)
+		}},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			c := customer()
+			patch, err := Decode([]byte(tf.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := patch.ApplyTo(&c); !tf.check(err) {
+				t.Fatal("unexpected error:", err)
+			}
+			if c.Name != "lasse" || c.Email != "lasse@jakobsen.dev" || c.Address.Street != "Somewhere 1" {
+				t.Fatalf("value modified by failed patch: %+v", c)
+			}
+		})
+	}
+}
+
+func TestApplyToInvalidTarget(t *testing.T) {
+	if err := (Patch{}).ApplyTo(customer()); err != ErrInvalidTarget {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package patch
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	escaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+	escapes   = strings.NewReplacer("~0", "", "~1", "")
+)
+
+// Pointer is a parsed RFC 6901 JSON Pointer, consisting of the unescaped
+// reference tokens. The empty Pointer refers to the whole document.
+type Pointer []string
+
+// ParsePointer will parse the given string as a JSON Pointer
+func ParsePointer(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, Error(ErrInvalidPointer, s)
+	}
+	ptr := strings.Split(s[1:], "/")
+	for i, t := range ptr {
+		if strings.Contains(escapes.Replace(t), "~") {
+			return nil, Error(ErrInvalidPointer, s)
+		}
+		ptr[i] = unescaper.Replace(t)
+	}
+	return ptr, nil
+}
+
+// String will return the escaped string representation of the pointer
+func (ptr Pointer) String() string {
+	var b strings.Builder
+	for _, t := range ptr {
+		b.WriteByte('/')
+		b.WriteString(escaper.Replace(t))
+	}
+	return b.String()
+}
+
+// isPrefixOf reports whether the pointer refers to a proper ancestor of
+// the value referenced by other.
+func (ptr Pointer) isPrefixOf(other Pointer) bool {
+	if len(ptr) >= len(other) {
+		return false
+	}
+	for i := range ptr {
+		if ptr[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// index will parse the given reference token as an array index, which must
+// be within the range [0, length).
+func index(t string, length int) (int, error) {
+	if t == "" || (len(t) > 1 && t[0] == '0') {
+		return 0, Error(ErrInvalidPointer, "invalid array index: "+t)
+	}
+	for i := 0; i < len(t); i++ {
+		if t[i] < '0' || t[i] > '9' {
+			return 0, Error(ErrInvalidPointer, "invalid array index: "+t)
+		}
+	}
+	i, err := strconv.Atoi(t)
+	if err != nil || i >= length {
+		return 0, Error(ErrPathNotFound, "array index out of range: "+t)
+	}
+	return i, nil
+}
@@ -0,0 +1,62 @@
+package required
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration, which is required on JSON (un)marshal, and
+// is represented as a string such as "1h30m" or "5s"
+type Duration struct {
+	value time.Duration
+	set   bool
+}
+
+// NewDuration returns a valid Duration with given value
+func NewDuration(value time.Duration) Duration {
+	return Duration{
+		value: value,
+		set:   true,
+	}
+}
+
+// Value will return the inner time.Duration
+func (d Duration) Value() time.Duration {
+	return d.value
+}
+
+// IsValueValid returns whether the contained value has been set
+func (d Duration) IsValueValid() error {
+	if !d.set {
+		return ErrEmptyDuration
+	}
+	return nil
+}
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if err := d.IsValueValid(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(d.value.String())
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarhsaler interface.
+// A null value will leave the Duration unset.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if string(data) == string(nullJSON) {
+		*d = Duration{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	value, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	*d = NewDuration(value)
+	return nil
+}
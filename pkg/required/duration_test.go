@@ -0,0 +1,64 @@
+package required
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type Timeout struct {
+	After Duration `json:"after"`
+}
+
+func TestNewDuration(t *testing.T) {
+	v := NewDuration(90 * time.Second)
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"1m30s"` {
+		t.Fatal(string(data))
+	}
+	var d Duration
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Value() != v.Value() {
+		t.Fatalf("%v != %v", d.Value(), v.Value())
+	}
+}
+
+func TestDurationValidation(t *testing.T) {
+	tt := []struct {
+		name   string
+		json   string
+		err    error
+		assert func(v interface{}) bool
+	}{
+		{"valid duration", `{"after": "5s"}`, nil, func(v interface{}) bool { return v.(Timeout).After.Value() == 5*time.Second }},
+		{"zero duration", `{"after": "0s"}`, nil, func(v interface{}) bool { return v.(Timeout).After.Value() == 0 }},
+		{"nil duration", `{}`, ErrEmptyDuration, skipAssert},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var timeout Timeout
+			err := Unmarshal([]byte(tf.json), &timeout)
+			assertError(t, err, tf.err)
+
+			if !tf.assert(timeout) {
+				t.Fatalf("Assertion Failed: %+v", timeout)
+			}
+		})
+	}
+}
+
+func TestDurationInvalid(t *testing.T) {
+	for _, data := range []string{`"5 seconds"`, `5000000000`} {
+		var d Duration
+		if err := d.UnmarshalJSON([]byte(data)); !errors.Is(err, ErrCannotUnmarshal) {
+			t.Fatal(data, err)
+		}
+	}
+}
@@ -3,22 +3,84 @@ package required
 import (
 	"errors"
 	"fmt"
+	"reflect"
 )
 
 var (
 	ErrCannotUnmarshal  = fmt.Errorf("json: cannot unmarshal given value")
 	ErrEmpty            = errors.New("type of required.RequiredInterface not allowed to be empty")
-	ErrEmptyBool        = errors.New("type of required.Bool not allowed to be empty")
-	ErrEmptyBoolSlice   = errors.New("type of required.BoolSlice not allowed to be empty")
-	ErrEmptyByteSlice   = errors.New("type of required.ByteSlice not allowed to be empty")
-	ErrEmptyFloatSlice  = errors.New("type of required.FloatSlice not allowed to be empty")
-	ErrEmptyFloat       = errors.New("type of required.Float not allowed to be empty")
-	ErrEmptyIntSlice    = errors.New("type of required.IntSlice not allowed to be empty")
-	ErrEmptyInt         = errors.New("type of required.Int not allowed to be empty")
-	ErrEmptyStringSlice = errors.New("type of required.StringSlice not allowed to be empty")
-	ErrEmptyString      = errors.New("type of required.String not allowed to be empty")
+	ErrEmptyBool        = EmptyError{Type: "required.Bool"}
+	ErrEmptyBoolSlice   = EmptyError{Type: "required.BoolSlice"}
+	ErrEmptyByteSlice   = EmptyError{Type: "required.ByteSlice"}
+	ErrEmptyDuration    = EmptyError{Type: "required.Duration"}
+	ErrEmptyFloatSlice  = EmptyError{Type: "required.FloatSlice"}
+	ErrEmptyFloat       = EmptyError{Type: "required.Float"}
+	ErrEmptyFloat32     = EmptyError{Type: "required.Float32"}
+	ErrEmptyIntSlice    = EmptyError{Type: "required.IntSlice"}
+	ErrEmptyInt         = EmptyError{Type: "required.Int"}
+	ErrEmptyInt64       = EmptyError{Type: "required.Int64"}
+	ErrEmptyStringSlice = EmptyError{Type: "required.StringSlice"}
+	ErrEmptyString      = EmptyError{Type: "required.String"}
+	ErrEmptyTime        = EmptyError{Type: "required.Time"}
+	ErrEmptyUint64      = EmptyError{Type: "required.Uint64"}
+	ErrEmptyUUID        = EmptyError{Type: "required.UUID"}
+	ErrInvalidUUID      = errors.New("invalid uuid")
 )
 
+// EmptyError is the typed sentinel error returned by a Value or Slice, which
+// is empty. Every instantiation has its own sentinel, which can be retrieved
+// with ErrEmptyValue and ErrEmptySlice, while errors.Is(err, ErrEmpty) will
+// match any of them.
+type EmptyError struct {
+	Type string
+}
+
+func (err EmptyError) Error() string {
+	return fmt.Sprintf("type of %s not allowed to be empty", err.Type)
+}
+
+// Is will report any EmptyError as being an ErrEmpty
+func (err EmptyError) Is(target error) bool {
+	return target == ErrEmpty
+}
+
+var (
+	emptyValueErrors = map[reflect.Type]error{
+		reflect.TypeOf(false):      ErrEmptyBool,
+		reflect.TypeOf(0.0):        ErrEmptyFloat,
+		reflect.TypeOf(float32(0)): ErrEmptyFloat32,
+		reflect.TypeOf(0):          ErrEmptyInt,
+		reflect.TypeOf(int64(0)):   ErrEmptyInt64,
+		reflect.TypeOf(""):         ErrEmptyString,
+		reflect.TypeOf(uint64(0)):  ErrEmptyUint64,
+	}
+	emptySliceErrors = map[reflect.Type]error{
+		reflect.TypeOf(false):   ErrEmptyBoolSlice,
+		reflect.TypeOf(byte(0)): ErrEmptyByteSlice,
+		reflect.TypeOf(0.0):     ErrEmptyFloatSlice,
+		reflect.TypeOf(0):       ErrEmptyIntSlice,
+		reflect.TypeOf(""):      ErrEmptyStringSlice,
+	}
+)
+
+// ErrEmptyValue returns the sentinel error of an empty Value[T]
+func ErrEmptyValue[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if err, ok := emptyValueErrors[t]; ok {
+		return err
+	}
+	return EmptyError{Type: fmt.Sprintf("required.Value[%v]", t)}
+}
+
+// ErrEmptySlice returns the sentinel error of an empty Slice[T]
+func ErrEmptySlice[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if err, ok := emptySliceErrors[t]; ok {
+		return err
+	}
+	return EmptyError{Type: fmt.Sprintf("required.Slice[%v]", t)}
+}
+
 type requiredErr struct {
 	err error
 	msg string
@@ -28,6 +90,10 @@ func (err requiredErr) Error() string {
 	return fmt.Sprintf("%s: %v", err.msg, err.err)
 }
 
+func (err requiredErr) Unwrap() error {
+	return err.err
+}
+
 // IsRequiredErr will type check the given error as a requiredErr
 // returning a boolean on whether the type check was successful
 func IsRequiredErr(err error) bool {
@@ -0,0 +1,9 @@
+package required
+
+// Float32 is a Float32 type, which is required on JSON (un)marshal
+type Float32 = Value[float32]
+
+// NewFloat32 returns a valid Float32 with given value
+func NewFloat32(value float32) Float32 {
+	return New(value)
+}
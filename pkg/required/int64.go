@@ -0,0 +1,9 @@
+package required
+
+// Int64 is a Int64 type, which is required on JSON (un)marshal
+type Int64 = Value[int64]
+
+// NewInt64 returns a valid Int64 with given value
+func NewInt64(value int64) Int64 {
+	return New(value)
+}
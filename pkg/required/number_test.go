@@ -0,0 +1,60 @@
+package required
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type Measurement struct {
+	Sequence Int64   `json:"sequence"`
+	Bytes    Uint64  `json:"bytes"`
+	Ratio    Float32 `json:"ratio"`
+}
+
+func TestNewNumbers(t *testing.T) {
+	v := Measurement{
+		Sequence: NewInt64(-1 << 62),
+		Bytes:    NewUint64(1 << 63),
+		Ratio:    NewFloat32(0.5),
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m Measurement
+	if err := Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m != v {
+		t.Fatalf("%+v != %+v", m, v)
+	}
+}
+
+func TestNumberValidation(t *testing.T) {
+	tt := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"valid", `{"sequence": 0, "bytes": 0, "ratio": 0}`, nil},
+		{"nil int64", `{"bytes": 1, "ratio": 1}`, ErrEmptyInt64},
+		{"nil uint64", `{"sequence": 1, "ratio": 1}`, ErrEmptyUint64},
+		{"nil float32", `{"sequence": 1, "bytes": 1}`, ErrEmptyFloat32},
+		{"negative uint64", `{"sequence": 1, "bytes": -1, "ratio": 1}`, ErrCannotUnmarshal},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var m Measurement
+			err := Unmarshal([]byte(tf.json), &m)
+			if tf.err == ErrCannotUnmarshal {
+				if !errors.Is(err, ErrCannotUnmarshal) {
+					t.Fatal(err)
+				}
+				return
+			}
+			assertError(t, err, tf.err)
+		})
+	}
+}
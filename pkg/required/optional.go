@@ -0,0 +1,113 @@
+package required
+
+import (
+	"encoding/json"
+)
+
+type optionalState uint8
+
+const (
+	stateAbsent optionalState = iota
+	stateNull
+	statePresent
+)
+
+var nullJSON = []byte("null")
+
+// Optional is a value which distinguishes between three states: absent from
+// the decoded JSON, explicitly set to null, or present with a value. This
+// makes it possible to tell "clear this field" apart from "leave it alone".
+// The zero value of Optional is absent.
+type Optional[T any] struct {
+	value T
+	state optionalState
+}
+
+// NewOptional returns an Optional, which is present with the given value
+func NewOptional[T any](value T) Optional[T] {
+	return Optional[T]{
+		value: value,
+		state: statePresent,
+	}
+}
+
+// NewNull returns an Optional, which has explicitly been set to null
+func NewNull[T any]() Optional[T] {
+	return Optional[T]{state: stateNull}
+}
+
+// IsAbsent returns whether the value was absent from the decoded JSON
+func (o Optional[T]) IsAbsent() bool {
+	return o.state == stateAbsent
+}
+
+// IsNull returns whether the value was explicitly set to null
+func (o Optional[T]) IsNull() bool {
+	return o.state == stateNull
+}
+
+// IsPresent returns whether the value was set to a non-null value
+func (o Optional[T]) IsPresent() bool {
+	return o.state == statePresent
+}
+
+// Value will return the contained value, which is the zero value of T
+// unless the Optional is present
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Get will return the contained value, and whether it is present
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.state == statePresent
+}
+
+// IsValueValid will validate the contained value, if it is present and
+// implements the Required interface. Use the `required` and `notnull` tags
+// to require an Optional to be present or non-null.
+func (o Optional[T]) IsValueValid() error {
+	if o.state != statePresent {
+		return nil
+	}
+	if req, ok := interface{}(o.value).(Required); ok {
+		return req.IsValueValid()
+	}
+	return nil
+}
+
+// MarshalJSON is an implementation of the json.Marshaler interface. Both
+// absent and null values are marshalled as null.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if o.state != statePresent {
+		return nullJSON, nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarhsaler interface. The
+// contained value is decoded using encoding/json, unless decoding with the
+// pkg/json package, which uses DecodeValue instead.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == string(nullJSON) {
+		var zero T
+		o.value, o.state = zero, stateNull
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.value, o.state = v, statePresent
+	return nil
+}
+
+// DecodeValue is an implementation of the Decoder interface
+func (o *Optional[T]) DecodeValue(null bool) interface{} {
+	var zero T
+	if null {
+		o.value, o.state = zero, stateNull
+		return nil
+	}
+	o.value, o.state = zero, statePresent
+	return &o.value
+}
@@ -0,0 +1,80 @@
+package required
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type PatchPerson struct {
+	Name  Optional[string] `json:"name"`
+	Age   Optional[int]    `json:"age"`
+	Email Optional[Email]  `json:"email"`
+}
+
+type Email string
+
+var errInvalidEmail = errors.New("invalid email")
+
+func (email Email) IsValueValid() error {
+	for i := range email {
+		if email[i] == '@' {
+			return nil
+		}
+	}
+	return errInvalidEmail
+}
+
+func TestOptionalStates(t *testing.T) {
+	var p PatchPerson
+	if err := json.Unmarshal([]byte(`{"name": "lasse", "age": null}`), &p); err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := p.Name.Get(); !ok || name != "lasse" || p.Name.IsNull() || p.Name.IsAbsent() {
+		t.Fatalf("name: %+v", p.Name)
+	}
+	if !p.Age.IsNull() || p.Age.IsPresent() || p.Age.IsAbsent() {
+		t.Fatalf("age: %+v", p.Age)
+	}
+	if !p.Email.IsAbsent() || p.Email.IsPresent() || p.Email.IsNull() {
+		t.Fatalf("email: %+v", p.Email)
+	}
+}
+
+func TestOptionalMarshal(t *testing.T) {
+	data, err := json.Marshal(PatchPerson{
+		Name: NewOptional("lasse"),
+		Age:  NewNull[int](),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"name":"lasse","age":null,"email":null}` {
+		t.Fatal(string(data))
+	}
+}
+
+func TestOptionalValidation(t *testing.T) {
+	tt := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"valid email", `{"email": "lasse@jakobsen.dev"}`, nil},
+		{"invalid email", `{"email": "lasse"}`, errInvalidEmail},
+		{"null email", `{"email": null}`, nil},
+		{"absent email", `{}`, nil},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var p PatchPerson
+			if err := json.Unmarshal([]byte(tf.json), &p); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.Email.IsValueValid(); err != tf.err {
+				t.Fatalf("expected %v, received: %v", tf.err, err)
+			}
+		})
+	}
+}
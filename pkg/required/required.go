@@ -2,13 +2,9 @@ package required
 
 import (
 	"encoding/json"
-	"fmt"
-	"reflect"
-)
 
-type Nullable struct {
-	value interface{}
-}
+	"github.com/Pungyeon/required/pkg/validate"
+)
 
 // Required is an interface which will enable the require.UnmarshalInterface parser,
 // to check whether a given object / interface has a valid contained value.
@@ -16,6 +12,19 @@ type Required interface {
 	IsValueValid() error
 }
 
+// Decoder is implemented by the types which contain a value of another
+// type, such as Value, Slice and Optional. The pkg/json package decodes the
+// contained value itself, rather than calling UnmarshalJSON, which can only
+// decode it using encoding/json, such that the tags and validation rules of
+// the contained type are applied.
+type Decoder interface {
+	// DecodeValue prepares the value for decoding. If the JSON value is
+	// null, the value is set as UnmarshalJSON would, and nil is returned.
+	// Otherwise, a pointer to the contained value is returned, into which
+	// the JSON value is decoded.
+	DecodeValue(null bool) interface{}
+}
+
 // ReturnIfError will iterate over a variadac error and return
 // an error if the given value is not nil
 func ReturnIfError(errs ...error) error {
@@ -27,7 +36,9 @@ func ReturnIfError(errs ...error) error {
 	return nil
 }
 
-// Unmarshal is a wrapping function of the json.UnmarshalInterface function
+// Unmarshal is a wrapping function of the json.UnmarshalInterface function,
+// which will Validate the decoded value. Only the first error found by
+// Validate is returned.
 func Unmarshal(data []byte, v interface{}) error {
 	return ReturnIfError(
 		json.Unmarshal(data, v),
@@ -35,61 +46,14 @@ func Unmarshal(data []byte, v interface{}) error {
 	)
 }
 
-// checkValues will check the values of a given interface and ensure
-// that if it contains a required struct, that the required values
-// are not empty
+// checkValues will Validate the given value, returning the first error
 func checkValues(v interface{}) error {
-	vo := reflect.ValueOf(v)
-	return checkIfRequired(vo, vo.Type().Name())
-}
-
-// checkIfRequired will ensure that the given value is valid
-// if it is a structure which fulfils the Required interface
-func checkIfRequired(vo reflect.Value, field string) error {
-	for vo.Kind() == reflect.Ptr {
-		vo = vo.Elem()
-	}
-	if vo.Kind() != reflect.Struct {
-		return nil
-	}
-	if req, ok := vo.Interface().(Required); ok {
-		return validateRequired(req, field)
-	}
-	return checkStructFieldsRequired(vo, field)
-}
-
-// validateRequired is a wrapper function for invoking the
-// Required interface and returning a detailed error, if
-// the value is invalid
-func validateRequired(req Required, field string) error {
-	if err := req.IsValueValid(); err != nil {
+	err := Validate(v)
+	if errs, ok := err.(validate.Errors); ok {
 		return requiredErr{
-			err: err,
-			msg: field,
+			err: errs[0].Err,
+			msg: errs[0].Path,
 		}
 	}
-	return nil
-}
-
-// checkStructFieldsRequired will inspect the given reflect.Value. If it contains
-// a required struct, it will check it's content, if it contains a struct
-// it will recursively invoke the function once more, if none of these apply
-// nil will be returned.
-func checkStructFieldsRequired(vo reflect.Value, parent string) error {
-	for i := 0; i < vo.NumField(); i++ {
-		if err := checkIfRequired(
-			vo.Field(i),
-			childString(parent, vo.Type().Field(i).Name),
-		); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func childString(parent string, child string) string {
-	if parent == "" {
-		return child
-	}
-	return fmt.Sprintf("%s.%s", parent, child)
+	return err
 }
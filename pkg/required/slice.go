@@ -0,0 +1,79 @@
+package required
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Slice is a slice of any type, which is required on JSON (un)marshal. A
+// Slice is valid once it contains at least one element. If the elements
+// implement the Required interface, they must also be valid themselves.
+type Slice[T any] struct {
+	value []T
+}
+
+// NewSlice returns a valid Slice with given value
+func NewSlice[T any](value []T) Slice[T] {
+	return Slice[T]{
+		value: value,
+	}
+}
+
+// Value will return the inner slice
+func (s Slice[T]) Value() []T {
+	return s.value
+}
+
+// IsValueValid returns whether the contained value has been set
+func (s Slice[T]) IsValueValid() error {
+	if len(s.value) == 0 {
+		return ErrEmptySlice[T]()
+	}
+	for _, v := range s.value {
+		if req, ok := interface{}(v).(Required); ok {
+			if err := req.IsValueValid(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (s Slice[T]) MarshalJSON() ([]byte, error) {
+	if err := s.IsValueValid(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(s.value)
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarhsaler interface.
+// A null value will leave the Slice unset. The elements are decoded using
+// encoding/json, unless decoding with the pkg/json package, which uses
+// DecodeValue instead.
+func (s *Slice[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == string(nullJSON) {
+		s.value = nil
+		return nil
+	}
+	var v []T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	if len(v) == 0 {
+		return ErrEmptySlice[T]()
+	}
+	s.value = v
+	return nil
+}
+
+// DecodeValue is an implementation of the Decoder interface. A null value
+// will leave the Slice unset, and an empty array is reported by
+// IsValueValid.
+func (s *Slice[T]) DecodeValue(null bool) interface{} {
+	s.value = nil
+	if null {
+		return nil
+	}
+	return &s.value
+}
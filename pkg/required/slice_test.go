@@ -0,0 +1,50 @@
+package required
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type Route struct {
+	Stops    Slice[Point] `json:"stops"`
+	Contacts Slice[Email] `json:"contacts"`
+}
+
+func TestNewSlice(t *testing.T) {
+	v := Route{
+		Stops:    NewSlice([]Point{{X: 1}, {Y: 2}}),
+		Contacts: NewSlice([]Email{"lasse@jakobsen.dev"}),
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r Route
+	if err := Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Stops.Value()) != 2 || r.Stops.Value()[1].Y != 2 || r.Contacts.Value()[0] != "lasse@jakobsen.dev" {
+		t.Fatalf("%+v", r)
+	}
+}
+
+func TestSliceValidation(t *testing.T) {
+	tt := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"valid", `{"stops": [{"x": 1}], "contacts": ["a@b.c"]}`, nil},
+		{"empty slice", `{"stops": [], "contacts": ["a@b.c"]}`, ErrEmptySlice[Point]()},
+		{"nil slice", `{"stops": [{"x": 1}]}`, ErrEmptySlice[Email]()},
+		{"invalid element", `{"stops": [{"x": 1}], "contacts": ["a@b.c", "lasse"]}`, errInvalidEmail},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var r Route
+			err := Unmarshal([]byte(tf.json), &r)
+			assertError(t, err, tf.err)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package required
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Layout is implemented by types describing the layout used by a
+// TimeLayout, as accepted by time.Parse and time.Format. This allows the
+// layout to be configured per field, as part of the field type.
+type Layout interface {
+	Layout() string
+}
+
+// RFC3339 is the Layout of RFC 3339 timestamps. Fractional seconds are
+// accepted when parsing, and kept when formatting.
+type RFC3339 struct{}
+
+// Layout returns the RFC 3339 layout
+func (RFC3339) Layout() string {
+	return time.RFC3339Nano
+}
+
+// Time is a time.Time, which is required on JSON (un)marshal, and is
+// represented as an RFC 3339 timestamp
+type Time = TimeLayout[RFC3339]
+
+// TimeLayout is a time.Time, which is required on JSON (un)marshal, and is
+// represented as a string formatted with the layout described by L
+type TimeLayout[L Layout] struct {
+	value time.Time
+	set   bool
+}
+
+// NewTime returns a valid Time with given value
+func NewTime(value time.Time) Time {
+	return NewTimeLayout[RFC3339](value)
+}
+
+// NewTimeLayout returns a valid TimeLayout with given value
+func NewTimeLayout[L Layout](value time.Time) TimeLayout[L] {
+	return TimeLayout[L]{
+		value: value,
+		set:   true,
+	}
+}
+
+// Value will return the inner time.Time
+func (t TimeLayout[L]) Value() time.Time {
+	return t.value
+}
+
+// IsValueValid returns whether the contained value has been set
+func (t TimeLayout[L]) IsValueValid() error {
+	if !t.set {
+		var l L
+		if _, ok := interface{}(l).(RFC3339); ok {
+			return ErrEmptyTime
+		}
+		return EmptyError{Type: fmt.Sprintf("required.TimeLayout[%v]", reflect.TypeOf(l))}
+	}
+	return nil
+}
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (t TimeLayout[L]) MarshalJSON() ([]byte, error) {
+	if err := t.IsValueValid(); err != nil {
+		return nil, err
+	}
+	var l L
+	return json.Marshal(t.value.Format(l.Layout()))
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarhsaler interface.
+// A null value will leave the TimeLayout unset.
+func (t *TimeLayout[L]) UnmarshalJSON(data []byte) error {
+	if string(data) == string(nullJSON) {
+		*t = TimeLayout[L]{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	var l L
+	value, err := time.Parse(l.Layout(), s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	*t = NewTimeLayout[L](value)
+	return nil
+}
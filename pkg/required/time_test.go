@@ -0,0 +1,71 @@
+package required
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type DateOnly struct{}
+
+func (DateOnly) Layout() string {
+	return "2006-01-02"
+}
+
+type Booking struct {
+	Created Time                 `json:"created"`
+	Day     TimeLayout[DateOnly] `json:"day"`
+}
+
+func TestNewTime(t *testing.T) {
+	created := time.Date(2020, 5, 17, 12, 30, 0, 500, time.UTC)
+	v := Booking{
+		Created: NewTime(created),
+		Day:     NewTimeLayout[DateOnly](time.Date(2020, 5, 18, 0, 0, 0, 0, time.UTC)),
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"created":"2020-05-17T12:30:00.0000005Z","day":"2020-05-18"}` {
+		t.Fatal(string(data))
+	}
+	var b Booking
+	if err := Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+	if !b.Created.Value().Equal(created) || b.Day.Value().Day() != 18 {
+		t.Fatalf("%+v", b)
+	}
+}
+
+func TestTimeValidation(t *testing.T) {
+	tt := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"valid", `{"created": "2020-05-17T12:30:00+02:00", "day": "2020-05-18"}`, nil},
+		{"missing rfc3339", `{"day": "2020-05-18"}`, ErrEmptyTime},
+		{"missing layout", `{"created": "2020-05-17T12:30:00Z"}`, EmptyError{Type: "required.TimeLayout[required.DateOnly]"}},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var b Booking
+			err := Unmarshal([]byte(tf.json), &b)
+			assertError(t, err, tf.err)
+		})
+	}
+}
+
+func TestTimeInvalidLayout(t *testing.T) {
+	var b Booking
+	if err := json.Unmarshal([]byte(`{"day": "18/05/2020"}`), &b); !errors.Is(err, ErrCannotUnmarshal) {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"created": "2020-05-18"}`), &b); !errors.Is(err, ErrCannotUnmarshal) {
+		t.Fatal(err)
+	}
+}
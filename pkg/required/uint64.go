@@ -0,0 +1,9 @@
+package required
+
+// Uint64 is a Uint64 type, which is required on JSON (un)marshal
+type Uint64 = Value[uint64]
+
+// NewUint64 returns a valid Uint64 with given value
+func NewUint64(value uint64) Uint64 {
+	return New(value)
+}
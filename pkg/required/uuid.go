@@ -0,0 +1,112 @@
+package required
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// UUID is an RFC 4122 UUID, which is required on JSON (un)marshal, and is
+// represented in its canonical textual form, such as
+// "f47ac10b-58cc-4372-a567-0e02b2c3d479". The nil UUID is considered empty.
+type UUID struct {
+	value [16]byte
+}
+
+// NewUUID returns a UUID with given value. The UUID is only valid, if the
+// given value is a valid UUID.
+func NewUUID(value [16]byte) UUID {
+	return UUID{
+		value: value,
+	}
+}
+
+// ParseUUID will parse the canonical textual form of a UUID. Both lower and
+// upper case hexadecimal digits are accepted.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+	var b [32]byte
+	copy(b[0:8], s[0:8])
+	copy(b[8:12], s[9:13])
+	copy(b[12:16], s[14:18])
+	copy(b[16:20], s[19:23])
+	copy(b[20:32], s[24:36])
+	if _, err := hex.Decode(u.value[:], b[:]); err != nil {
+		return UUID{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+	if err := u.IsValueValid(); err != nil {
+		return UUID{}, err
+	}
+	return u, nil
+}
+
+// Value will return the inner bytes of the UUID
+func (u UUID) Value() [16]byte {
+	return u.value
+}
+
+// Version returns the version of the UUID
+func (u UUID) Version() int {
+	return int(u.value[6] >> 4)
+}
+
+// String returns the canonical textual form of the UUID
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u.value[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u.value[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u.value[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u.value[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:36], u.value[10:16])
+	return string(b[:])
+}
+
+// IsValueValid returns whether the contained value has been set, and is a
+// UUID of the RFC 4122 variant with a known version. Versions 6 to 8, which
+// were added by RFC 9562, are accepted as well.
+func (u UUID) IsValueValid() error {
+	if u.value == [16]byte{} {
+		return ErrEmptyUUID
+	}
+	if u.value[8]&0xc0 != 0x80 {
+		return fmt.Errorf("%w: unknown variant: %s", ErrInvalidUUID, u)
+	}
+	if v := u.Version(); v < 1 || v > 8 {
+		return fmt.Errorf("%w: unknown version %d: %s", ErrInvalidUUID, v, u)
+	}
+	return nil
+}
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (u UUID) MarshalJSON() ([]byte, error) {
+	if err := u.IsValueValid(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarhsaler interface.
+// A null value will leave the UUID unset.
+func (u *UUID) UnmarshalJSON(data []byte) error {
+	if string(data) == string(nullJSON) {
+		*u = UUID{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	value, err := ParseUUID(s)
+	if err != nil {
+		return err
+	}
+	*u = value
+	return nil
+}
@@ -0,0 +1,89 @@
+package required
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type Order struct {
+	ID UUID `json:"id"`
+}
+
+func TestParseUUID(t *testing.T) {
+	tt := []struct {
+		uuid    string
+		err     error
+		version int
+	}{
+		{"f47ac10b-58cc-4372-a567-0e02b2c3d479", nil, 4},
+		{"F47AC10B-58CC-4372-A567-0E02B2C3D479", nil, 4},
+		{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", nil, 1},
+		{"017f22e2-79b0-7cc3-98c4-dc0c0c07398f", nil, 7},
+		{"00000000-0000-0000-0000-000000000000", ErrEmptyUUID, 0},
+		{"f47ac10b58cc4372a5670e02b2c3d479", ErrInvalidUUID, 0},
+		{"f47ac10b-58cc-4372-a567-0e02b2c3d47", ErrInvalidUUID, 0},
+		{"g47ac10b-58cc-4372-a567-0e02b2c3d479", ErrInvalidUUID, 0},
+		{"f47ac10b-58cc-0372-a567-0e02b2c3d479", ErrInvalidUUID, 0},
+		{"f47ac10b-58cc-4372-c567-0e02b2c3d479", ErrInvalidUUID, 0},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.uuid, func(t *testing.T) {
+			u, err := ParseUUID(tf.uuid)
+			if !errors.Is(err, tf.err) {
+				t.Fatalf("expected %v, received: %v", tf.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if u.Version() != tf.version {
+				t.Fatal("unexpected version:", u.Version())
+			}
+			if _, err := ParseUUID(u.String()); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestUUIDValidation(t *testing.T) {
+	tt := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"valid uuid", `{"id": "f47ac10b-58cc-4372-a567-0e02b2c3d479"}`, nil},
+		{"nil uuid", `{}`, ErrEmptyUUID},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var o Order
+			err := Unmarshal([]byte(tf.json), &o)
+			assertError(t, err, tf.err)
+		})
+	}
+
+	var o Order
+	if err := json.Unmarshal([]byte(`{"id": "not-a-uuid"}`), &o); !errors.Is(err, ErrInvalidUUID) {
+		t.Fatal(err)
+	}
+}
+
+func TestUUIDMarshal(t *testing.T) {
+	u, err := ParseUUID("F47AC10B-58CC-4372-A567-0E02B2C3D479")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(Order{ID: u})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"id":"f47ac10b-58cc-4372-a567-0e02b2c3d479"}` {
+		t.Fatal(string(data))
+	}
+	if _, err := json.Marshal(Order{}); !errors.Is(err, ErrEmptyUUID) {
+		t.Fatal(err)
+	}
+}
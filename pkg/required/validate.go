@@ -0,0 +1,139 @@
+package required
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Pungyeon/required/pkg/structtag"
+	"github.com/Pungyeon/required/pkg/validate"
+)
+
+// Validate will walk the given value, applying the same rules as when
+// decoding JSON with the pkg/json package: the `required`, `notnull` and
+// conditional options of the json tag, the `validate` tag, and the Required
+// and validate.Validator interfaces. This makes it possible to validate
+// values which have been constructed in code, or decoded from any other
+// format. As a Go value cannot distinguish between an absent field and a
+// field with a zero value, fields with a zero value are treated as absent:
+// they do not satisfy the `required` option, and their `validate` rules are
+// not applied.
+//
+// All errors found are returned as a validate.Errors, in which the path of
+// every error is given using the JSON names of fields.
+func Validate(v interface{}) error {
+	vo := reflect.ValueOf(v)
+	if !vo.IsValid() {
+		return nil
+	}
+	if vo.Kind() != reflect.Ptr {
+		// validate an addressable copy, such that methods with pointer
+		// receivers are found
+		ptr := reflect.New(vo.Type())
+		ptr.Elem().Set(vo)
+		vo = ptr
+	}
+	w := walker{seen: map[visit]bool{}}
+	if err := w.walk(vo, ""); err != nil {
+		return err
+	}
+	if len(w.errs) > 0 {
+		return w.errs
+	}
+	return nil
+}
+
+type walker struct {
+	errs validate.Errors
+	seen map[visit]bool
+}
+
+// visit identifies a pointer, which has already been walked, such that
+// cyclic values are only validated once
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func (w *walker) walk(v reflect.Value, path string) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			key := visit{ptr: v.Pointer(), typ: v.Type()}
+			if w.seen[key] {
+				return nil
+			}
+			w.seen[key] = true
+		}
+		v = v.Elem()
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if err := w.walkStruct(v, path); err != nil {
+			return err
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(v.Index(i), validate.Join(path, validate.Index(i))); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			// map values are not addressable, so they are copied
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if err := w.walk(elem, validate.Join(path, fmt.Sprint(key))); err != nil {
+				return err
+			}
+		}
+	}
+
+	value := v.Interface()
+	if v.CanAddr() {
+		value = v.Addr().Interface()
+	}
+	if req, ok := value.(Required); ok {
+		if err := req.IsValueValid(); err != nil {
+			w.errs = append(w.errs, validate.FieldError{Path: path, Err: err})
+		}
+	}
+	if validator, ok := value.(validate.Validator); ok {
+		w.errs = append(w.errs, validate.NewContext(path).Run(validator)...)
+	}
+	return nil
+}
+
+func (w *walker) walkStruct(v reflect.Value, path string) error {
+	tags, err := structtag.FromValue(v)
+	if err != nil {
+		return err
+	}
+	w.errs = append(w.errs, tags.Check(v, tags.StateOf(v)).Prefix(path)...)
+
+	for _, key := range tags.Keys() {
+		tag := tags.Tags[key]
+		field := v.Field(tag.FieldIndex)
+		if !field.CanInterface() {
+			continue
+		}
+		fieldPath := validate.Join(path, key)
+		if err := w.walk(field, fieldPath); err != nil {
+			return err
+		}
+		if !tag.Rules.IsEmpty() && !field.IsZero() {
+			w.errs = append(w.errs, tag.Rules.Validate(field).Prefix(fieldPath)...)
+		}
+	}
+	return nil
+}
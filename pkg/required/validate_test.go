@@ -0,0 +1,115 @@
+package required
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Pungyeon/required/pkg/validate"
+)
+
+type Address struct {
+	Street  string `json:"street,required"`
+	Country string `json:"country" validate:"len=2"`
+}
+
+type Member struct {
+	Name      String             `json:"name"`
+	Email     Optional[Email]    `json:"email,notnull"`
+	Role      string             `json:"role,required"`
+	Phone     string             `json:"phone,required_without=email"`
+	Address   *Address           `json:"address"`
+	Previous  []Address          `json:"previous"`
+	Labels    map[string]Address `json:"labels"`
+	Manager   *Member            `json:"manager"`
+	unchecked String
+}
+
+func (m *Member) Validate(ctx *validate.Context) error {
+	if m.Manager == m {
+		return errors.New("must not manage themselves")
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	valid := Member{
+		Name:  NewString("lasse"),
+		Email: NewOptional(Email("lasse@jakobsen.dev")),
+		Role:  "admin",
+	}
+	if err := Validate(valid); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(&valid); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := Member{
+		Email:    NewOptional(Email("lasse.jakobsen.dev")),
+		Address:  &Address{Country: "DNK"},
+		Previous: []Address{{Street: "Dingvej 1"}, {}},
+		Labels:   map[string]Address{"home": {Street: "Dongvej 2", Country: "D"}},
+	}
+	invalid.Manager = &invalid
+
+	err := Validate(&invalid)
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatal("expected validation errors:", err)
+	}
+	expected := []string{
+		"role",
+		"name",
+		"email",
+		"address.street",
+		"address.country",
+		"previous[1].street",
+		"labels.home.country",
+		"",
+	}
+	paths := make([]string, len(errs))
+	for i, err := range errs {
+		paths[i] = err.Path
+	}
+	if len(paths) != len(expected) {
+		t.Fatalf("unexpected errors: %q: %v", paths, err)
+	}
+	for i := range expected {
+		if paths[i] != expected[i] {
+			t.Fatalf("unexpected errors: %q: %v", paths, err)
+		}
+	}
+	if !errors.Is(err, ErrEmptyString) || !errors.Is(err, errInvalidEmail) {
+		t.Fatal("expected the errors of the Required interface:", err)
+	}
+}
+
+func TestValidateNil(t *testing.T) {
+	if err := Validate(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate((*Member)(nil)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnmarshalValidatesTags(t *testing.T) {
+	var a Address
+	if err := Unmarshal([]byte(`{"country": "DK"}`), &a); !IsRequiredErr(err) {
+		t.Fatal("expected required error:", err)
+	}
+	if err := Unmarshal([]byte(`{"street": "Dingvej 1", "country": "DK"}`), &a); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	err := Validate(Member{Name: NewString("lasse"), Role: "admin"})
+	var errs validate.Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "phone" {
+		t.Fatal("expected phone to be required:", err)
+	}
+	if err := Validate(Member{Name: NewString("lasse"), Role: "admin", Phone: "12345678"}); err != nil {
+		t.Fatal(err)
+	}
+}
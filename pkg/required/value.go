@@ -0,0 +1,82 @@
+package required
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Value is a value of any type, which is required on JSON (un)marshal. A
+// Value is valid once it has been set to a non-null value, which for string
+// types must also be non-empty. If the contained value implements the
+// Required interface, it must also be valid itself.
+type Value[T any] struct {
+	value T
+	set   bool
+}
+
+// New returns a valid Value with given value
+func New[T any](value T) Value[T] {
+	return Value[T]{
+		value: value,
+		set:   true,
+	}
+}
+
+// Value will return the inner value, which is the zero value of T, if the
+// Value has not been set
+func (v Value[T]) Value() T {
+	return v.value
+}
+
+// IsValueValid returns whether the contained value has been set
+func (v Value[T]) IsValueValid() error {
+	if !v.set || isEmptyString(v.value) {
+		return ErrEmptyValue[T]()
+	}
+	if req, ok := interface{}(v.value).(Required); ok {
+		return req.IsValueValid()
+	}
+	return nil
+}
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (v Value[T]) MarshalJSON() ([]byte, error) {
+	if err := v.IsValueValid(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v.value)
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarhsaler interface.
+// A null value will leave the Value unset. The contained value is decoded
+// using encoding/json, unless decoding with the pkg/json package, which
+// uses DecodeValue instead.
+func (v *Value[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == string(nullJSON) {
+		*v = Value[T]{}
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: %v", ErrCannotUnmarshal, err)
+	}
+	*v = New(value)
+	return nil
+}
+
+// DecodeValue is an implementation of the Decoder interface. A null value
+// will leave the Value unset.
+func (v *Value[T]) DecodeValue(null bool) interface{} {
+	if null {
+		*v = Value[T]{}
+		return nil
+	}
+	*v = Value[T]{set: true}
+	return &v.value
+}
+
+func isEmptyString(v interface{}) bool {
+	vo := reflect.ValueOf(v)
+	return vo.Kind() == reflect.String && vo.Len() == 0
+}
@@ -0,0 +1,103 @@
+package required
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type Event struct {
+	ID      Value[int64]     `json:"id"`
+	Count   Value[uint]      `json:"count"`
+	At      Value[time.Time] `json:"at"`
+	Origin  Value[Point]     `json:"origin"`
+	Contact Value[Email]     `json:"contact"`
+}
+
+func TestNewValue(t *testing.T) {
+	at := time.Date(2020, 5, 17, 12, 0, 0, 0, time.UTC)
+	v := Event{
+		ID:      New(int64(1) << 40),
+		Count:   New(uint(3)),
+		At:      New(at),
+		Origin:  New(Point{X: 1, Y: 2}),
+		Contact: New(Email("lasse@jakobsen.dev")),
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e Event
+	if err := Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.ID.Value() != 1<<40 || e.Count.Value() != 3 || !e.At.Value().Equal(at) ||
+		e.Origin.Value() != (Point{X: 1, Y: 2}) || e.Contact.Value() != "lasse@jakobsen.dev" {
+		t.Fatalf("%+v", e)
+	}
+}
+
+func TestValueValidation(t *testing.T) {
+	tt := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"valid", `{"id": 1, "count": 2, "at": "2020-05-17T12:00:00Z", "origin": {"x": 1}, "contact": "a@b.c"}`, nil},
+		{"missing time", `{"id": 1, "count": 2, "origin": {"x": 1}, "contact": "a@b.c"}`, ErrEmptyValue[time.Time]()},
+		{"null struct", `{"id": 1, "count": 2, "at": "2020-05-17T12:00:00Z", "origin": null, "contact": "a@b.c"}`, ErrEmptyValue[Point]()},
+		{"empty user string", `{"id": 1, "count": 2, "at": "2020-05-17T12:00:00Z", "origin": {}, "contact": ""}`, ErrEmptyValue[Email]()},
+		{"invalid user value", `{"id": 1, "count": 2, "at": "2020-05-17T12:00:00Z", "origin": {}, "contact": "lasse"}`, errInvalidEmail},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			var e Event
+			err := Unmarshal([]byte(tf.json), &e)
+			assertError(t, err, tf.err)
+		})
+	}
+}
+
+func TestValueUnmarshalError(t *testing.T) {
+	var v Value[uint]
+	if err := v.UnmarshalJSON([]byte(`-1`)); !errors.Is(err, ErrCannotUnmarshal) {
+		t.Fatal(err)
+	}
+	if err := v.IsValueValid(); err != ErrEmptyValue[uint]() {
+		t.Fatal(err)
+	}
+}
+
+func TestEmptyErrors(t *testing.T) {
+	tt := []struct {
+		err      error
+		expected error
+		message  string
+	}{
+		{ErrEmptyValue[string](), ErrEmptyString, "type of required.String not allowed to be empty"},
+		{ErrEmptySlice[byte](), ErrEmptyByteSlice, "type of required.ByteSlice not allowed to be empty"},
+		{ErrEmptyValue[time.Time](), ErrEmptyValue[time.Time](), "type of required.Value[time.Time] not allowed to be empty"},
+		{ErrEmptySlice[Point](), ErrEmptySlice[Point](), "type of required.Slice[required.Point] not allowed to be empty"},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.message, func(t *testing.T) {
+			if tf.err != tf.expected || tf.err.Error() != tf.message {
+				t.Fatal(tf.err)
+			}
+			if !errors.Is(tf.err, ErrEmpty) {
+				t.Fatal("not an ErrEmpty:", tf.err)
+			}
+		})
+	}
+	if ErrEmptyValue[int64]() == ErrEmptyValue[int]() {
+		t.Fatal("sentinel errors of different types must not be equal")
+	}
+}
@@ -0,0 +1,7 @@
+package schema
+
+import "errors"
+
+// ErrUnsupportedType is returned for types, which cannot be represented in
+// JSON, such as channels and functions
+var ErrUnsupportedType = errors.New("unsupported type")
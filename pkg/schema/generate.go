@@ -0,0 +1,436 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pungyeon/required/pkg/required"
+	"github.com/Pungyeon/required/pkg/structtag"
+)
+
+var (
+	schemerType     = reflect.TypeOf((*Schemer)(nil)).Elem()
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	requiredType    = reflect.TypeOf((*required.Required)(nil)).Elem()
+	timeType        = reflect.TypeOf(time.Time{})
+	timeDuration    = reflect.TypeOf(time.Duration(0))
+	uuidType        = reflect.TypeOf(required.UUID{})
+	durationType    = reflect.TypeOf(required.Duration{})
+	rfc3339Type     = reflect.TypeOf(required.Time{})
+	requiredPkgPath = uuidType.PkgPath()
+)
+
+// For returns the JSON Schema of the type T
+func For[T any]() (*Schema, error) {
+	return Generate(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Generate returns the JSON Schema of the given type. Named structs which
+// are used more than once, or which are recursive, are added to $defs and
+// referred to using $ref, while all other types are given inline.
+func Generate(t reflect.Type) (*Schema, error) {
+	g := &generator{
+		root:      t,
+		uses:      map[reflect.Type]int{},
+		recursive: map[reflect.Type]bool{},
+		names:     map[reflect.Type]string{},
+		taken:     map[string]bool{},
+		defs:      map[string]*Schema{},
+	}
+	for t.Kind() == reflect.Ptr {
+		g.root = g.root.Elem()
+		t = t.Elem()
+	}
+	g.count(g.root, map[reflect.Type]bool{})
+
+	s, err := g.generateType(g.root)
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = Draft
+	if len(g.defs) > 0 {
+		s.Defs = g.defs
+	}
+	return s, nil
+}
+
+type generator struct {
+	root      reflect.Type
+	started   bool
+	uses      map[reflect.Type]int
+	recursive map[reflect.Type]bool
+	names     map[reflect.Type]string
+	taken     map[string]bool
+	defs      map[string]*Schema
+}
+
+// wrapper returns the kind of type of the required package, which wraps a
+// value of another type, such as required.Value[T]. An empty string is
+// returned for all other types.
+func wrapper(t reflect.Type) string {
+	if t.PkgPath() != requiredPkgPath || t.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, prefix := range []string{"Value[", "Slice[", "Optional["} {
+		if strings.HasPrefix(t.Name(), prefix) {
+			return prefix[:len(prefix)-1]
+		}
+	}
+	return ""
+}
+
+// isLeaf returns whether the schema of the given type is not generated from
+// the values it contains
+func isLeaf(t reflect.Type) bool {
+	if t.Implements(schemerType) || reflect.PtrTo(t).Implements(schemerType) {
+		return true
+	}
+	if wrapper(t) != "" {
+		return false
+	}
+	return t == timeType || t.PkgPath() == requiredPkgPath ||
+		t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)
+}
+
+// children returns the types of the values, which are contained in values
+// of the given type
+func children(t reflect.Type) []reflect.Type {
+	if isLeaf(t) {
+		return nil
+	}
+	if wrapper(t) != "" {
+		return []reflect.Type{t.Field(0).Type}
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return []reflect.Type{t.Elem()}
+	case reflect.Struct:
+		var types []reflect.Type
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				types = append(types, f.Type)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// count will count the number of times every named struct is used, and
+// find the structs which are recursive
+func (g *generator) count(t reflect.Type, stack map[reflect.Type]bool) {
+	if t.Kind() == reflect.Struct && t.Name() != "" && !isLeaf(t) && wrapper(t) == "" {
+		g.uses[t]++
+		if stack[t] {
+			g.recursive[t] = true
+			return
+		}
+		if g.uses[t] > 1 {
+			return
+		}
+		stack[t] = true
+		defer delete(stack, t)
+	}
+	for _, child := range children(t) {
+		g.count(child, stack)
+	}
+}
+
+// generate returns the schema of the given type, in which pointers are
+// nullable
+func (g *generator) generate(t reflect.Type) (*Schema, error) {
+	var isPtr bool
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		isPtr = true
+	}
+	s, err := g.generateType(t)
+	if err != nil || !isPtr {
+		return s, err
+	}
+	return nullable(s), nil
+}
+
+func nullable(s *Schema) *Schema {
+	if len(s.Type) == 0 || s.Ref != "" {
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	}
+	for _, t := range s.Type {
+		if t == "null" {
+			return s
+		}
+	}
+	s.Type = append(append(Types{}, s.Type...), "null")
+	if len(s.Enum) > 0 {
+		s.Enum = append(s.Enum, nil)
+	}
+	return s
+}
+
+func (g *generator) generateType(t reflect.Type) (*Schema, error) {
+	if s, ok := g.special(t); ok {
+		return s, nil
+	}
+	switch kind := wrapper(t); kind {
+	case "Value":
+		s, err := g.generateType(t.Field(0).Type)
+		if err == nil && t.Field(0).Type.Kind() == reflect.String {
+			s.MinLength = intPtr(1)
+		}
+		return s, err
+	case "Slice":
+		s, err := g.generateType(t.Field(0).Type)
+		if err == nil {
+			s.MinItems = intPtr(1)
+		}
+		return s, err
+	case "Optional":
+		s, err := g.generate(t.Field(0).Type)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(s), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}, Minimum: floatPtr(0)}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}, nil
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.generate(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := &Schema{Type: Types{"array"}, Items: items}
+		if t.Kind() == reflect.Array {
+			s.MinItems, s.MaxItems = intPtr(t.Len()), intPtr(t.Len())
+		}
+		return s, nil
+	case reflect.Map:
+		values, err := g.generate(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Types{"object"}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return g.structRef(t)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, t)
+}
+
+// special returns the schema of types, which describe their own schema or
+// are marshalled differently from their kind
+func (g *generator) special(t reflect.Type) (*Schema, bool) {
+	if t.Implements(schemerType) {
+		return copySchema(reflect.Zero(t).Interface().(Schemer).JSONSchema()), true
+	}
+	if reflect.PtrTo(t).Implements(schemerType) {
+		return copySchema(reflect.New(t).Interface().(Schemer).JSONSchema()), true
+	}
+	switch {
+	case t == timeType || t == rfc3339Type:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}, true
+	case t == timeDuration:
+		return &Schema{Type: Types{"integer"}}, true
+	case t == uuidType:
+		return &Schema{Type: Types{"string"}, Format: "uuid"}, true
+	case t == durationType:
+		return &Schema{Type: Types{"string"}, MinLength: intPtr(1)}, true
+	case t.PkgPath() == requiredPkgPath && strings.HasPrefix(t.Name(), "TimeLayout["):
+		return &Schema{Type: Types{"string"}}, true
+	case wrapper(t) == "" &&
+		(t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)):
+		// the JSON representation of the type is unknown
+		return &Schema{}, true
+	}
+	return nil, false
+}
+
+func copySchema(s *Schema) *Schema {
+	if s == nil {
+		return &Schema{}
+	}
+	c := *s
+	return &c
+}
+
+// structRef returns the schema of the given struct, or a reference to it
+func (g *generator) structRef(t reflect.Type) (*Schema, error) {
+	if t == g.root {
+		if g.started {
+			return &Schema{Ref: "#"}, nil
+		}
+		g.started = true
+		return g.object(t)
+	}
+	if t.Name() == "" || (g.uses[t] < 2 && !g.recursive[t]) {
+		return g.object(t)
+	}
+	name, ok := g.names[t]
+	if !ok {
+		name = g.name(t)
+		g.names[t] = name
+		s, err := g.object(t)
+		if err != nil {
+			return nil, err
+		}
+		g.defs[name] = s
+	}
+	return &Schema{Ref: "#/$defs/" + name}, nil
+}
+
+// name returns a unique name in $defs for the given type
+func (g *generator) name(t reflect.Type) string {
+	name := sanitize(t.Name())
+	if g.taken[name] {
+		pkg := t.PkgPath()
+		name = sanitize(pkg[strings.LastIndexByte(pkg, '/')+1:]) + "_" + name
+	}
+	for i, base := 2, name; g.taken[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.taken[name] = true
+	return name
+}
+
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r == '.' ||
+			'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// object returns the schema of the fields of the given struct
+func (g *generator) object(t reflect.Type) (*Schema, error) {
+	tags, err := structtag.FromValue(reflect.New(t).Elem())
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{
+		Type:       Types{"object"},
+		Properties: map[string]*Schema{},
+	}
+	for _, key := range tags.Keys() {
+		tag := tags.Tags[key]
+		f := t.Field(tag.FieldIndex)
+		if f.PkgPath != "" {
+			continue
+		}
+		ft := f.Type
+		for tag.NotNull && ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		fs, err := g.generate(ft)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if err := constrain(fs, tag.Rules, ft); err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if tag.HasDefault {
+			fs.Default = json.RawMessage(tag.Default)
+		}
+		s.Properties[key] = fs
+
+		if tag.Required || isRequired(f.Type) {
+			s.Required = append(s.Required, key)
+		}
+		for _, c := range tag.Conditions {
+			g.condition(s, t, tags, key, c)
+		}
+	}
+	for _, group := range tags.Groups {
+		options := make([]*Schema, len(group.Fields))
+		for i, field := range group.Fields {
+			options[i] = &Schema{Required: []string{field}}
+		}
+		if group.Kind == structtag.OneOf {
+			s.AllOf = append(s.AllOf, &Schema{OneOf: options})
+		} else {
+			s.AllOf = append(s.AllOf, &Schema{AnyOf: options})
+		}
+	}
+	return s, nil
+}
+
+// isRequired returns whether the given field type is one of the types of
+// the required package, which must be present
+func isRequired(t reflect.Type) bool {
+	if t.PkgPath() != requiredPkgPath || wrapper(t) == "Optional" {
+		return false
+	}
+	return t.Implements(requiredType) || reflect.PtrTo(t).Implements(requiredType)
+}
+
+// condition will add the conditional requirement of the field with the given
+// key to the schema of its struct
+func (g *generator) condition(s *Schema, t reflect.Type, tags structtag.Tags, key string, c structtag.Condition) {
+	then := &Schema{Required: []string{key}}
+	switch c.Kind {
+	case structtag.RequiredWith:
+		if s.DependentRequired == nil {
+			s.DependentRequired = map[string][]string{}
+		}
+		s.DependentRequired[c.Field] = append(s.DependentRequired[c.Field], key)
+	case structtag.RequiredWithout:
+		s.AllOf = append(s.AllOf, &Schema{
+			If:   &Schema{Not: &Schema{Required: []string{c.Field}}},
+			Then: then,
+		})
+	case structtag.RequiredIf, structtag.RequiredUnless:
+		other := t.Field(tags.Tags[c.Field].FieldIndex).Type
+		is := &Schema{
+			Properties: map[string]*Schema{c.Field: {Const: constant(c.Value, other)}},
+			Required:   []string{c.Field},
+		}
+		if c.Kind == structtag.RequiredUnless {
+			is = &Schema{Not: is}
+		}
+		s.AllOf = append(s.AllOf, &Schema{If: is, Then: then})
+	}
+}
+
+// constant returns the JSON value of the value of a condition, given the
+// type of the field it is compared to
+func constant(value string, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
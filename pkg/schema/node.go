@@ -0,0 +1,235 @@
+package schema
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/Pungyeon/required/pkg/lexer"
+	"github.com/Pungyeon/required/pkg/token"
+)
+
+type kind uint8
+
+const (
+	kindNull kind = iota
+	kindBoolean
+	kindNumber
+	kindString
+	kindArray
+	kindObject
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindNull:
+		return "null"
+	case kindBoolean:
+		return "boolean"
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindArray:
+		return "array"
+	case kindObject:
+		return "object"
+	}
+	return "unknown"
+}
+
+// node is a parsed JSON value, in which the order of object members is
+// preserved, such that violations are reported in document order
+type node struct {
+	kind    kind
+	boolean bool
+	number  float64
+	text    string
+	items   []*node
+	members []member
+}
+
+type member struct {
+	key   string
+	value *node
+}
+
+// get returns the value of the object member with the given key
+func (n *node) get(key string) (*node, bool) {
+	if n.kind != kindObject {
+		return nil, false
+	}
+	for _, m := range n.members {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
+
+func (n *node) isInteger() bool {
+	return n.kind == kindNumber && n.number == math.Trunc(n.number) && !math.IsInf(n.number, 0)
+}
+
+// equal returns whether the given values are equal, as defined by JSON
+// Schema: numbers are equal if they have the same mathematical value, and
+// the order of object members is insignificant
+func equal(a, b *node) bool {
+	if a.kind != b.kind {
+		return false
+	}
+	switch a.kind {
+	case kindBoolean:
+		return a.boolean == b.boolean
+	case kindNumber:
+		return a.number == b.number
+	case kindString:
+		return a.text == b.text
+	case kindArray:
+		if len(a.items) != len(b.items) {
+			return false
+		}
+		for i := range a.items {
+			if !equal(a.items[i], b.items[i]) {
+				return false
+			}
+		}
+	case kindObject:
+		if len(a.members) != len(b.members) {
+			return false
+		}
+		for _, m := range a.members {
+			other, ok := b.get(m.key)
+			if !ok || !equal(m.value, other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parse will parse the given JSON document into a node
+func parse(data []byte) (*node, error) {
+	p := &nodeParser{lexer: lexer.NewLexer(data)}
+	if err := p.next(); err != nil {
+		if err == io.EOF {
+			return nil, token.Error(token.ErrInvalidJSON, "empty document")
+		}
+		return nil, err
+	}
+	n, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if p.current.Type != token.Unknown {
+		return nil, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected data after document: %s", p.current))
+	}
+	return n, nil
+}
+
+type nodeParser struct {
+	lexer   *lexer.Lexer
+	current token.Token
+}
+
+func (p *nodeParser) next() error {
+	var err error
+	p.current, err = p.lexer.Next()
+	return err
+}
+
+// advance will move to the next token, which may be the end of the document
+func (p *nodeParser) advance() error {
+	if err := p.next(); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+func (p *nodeParser) value() (*node, error) {
+	switch p.current.Type {
+	case token.OpenCurly:
+		return p.object()
+	case token.OpenBrace:
+		return p.array()
+	case token.Null:
+		return &node{kind: kindNull}, p.advance()
+	case token.Boolean:
+		return &node{kind: kindBoolean, boolean: p.current.ToString() == "true"}, p.advance()
+	case token.String:
+		return &node{kind: kindString, text: p.current.ToString()}, p.advance()
+	case token.Integer, token.Float:
+		f, err := strconv.ParseFloat(p.current.ToString(), 64)
+		if err != nil {
+			return nil, token.Error(token.ErrInvalidValue, fmt.Sprintf("%v: %v", p.current, err))
+		}
+		return &node{kind: kindNumber, number: f, text: p.current.ToString()}, p.advance()
+	}
+	return nil, token.Error(token.ErrInvalidJSON, fmt.Sprintf("unexpected token: %s", p.current))
+}
+
+func (p *nodeParser) object() (*node, error) {
+	n := &node{kind: kindObject}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	for p.current.Type != token.ClosingCurly {
+		if p.current.Type != token.String {
+			return nil, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected object field, got: %s", p.current))
+		}
+		key := p.current.ToString()
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.current.Type != token.Colon {
+			return nil, token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected colon token: %s", p.current))
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		n.members = append(n.members, member{key: key, value: value})
+		if err := p.separator(token.ClosingCurly); err != nil {
+			return nil, err
+		}
+	}
+	return n, p.advance()
+}
+
+func (p *nodeParser) array() (*node, error) {
+	n := &node{kind: kindArray}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	for p.current.Type != token.ClosingBrace {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		n.items = append(n.items, value)
+		if err := p.separator(token.ClosingBrace); err != nil {
+			return nil, err
+		}
+	}
+	return n, p.advance()
+}
+
+func (p *nodeParser) separator(closing token.TokenType) error {
+	switch p.current.Type {
+	case token.Comma:
+		if err := p.next(); err != nil {
+			return err
+		}
+		if p.current.Type == closing {
+			return token.Error(token.ErrInvalidJSON, "trailing comma")
+		}
+		return nil
+	case closing:
+		return nil
+	}
+	return token.Error(token.ErrInvalidJSON, fmt.Sprintf("expected %s or comma: %s", closing, p.current))
+}
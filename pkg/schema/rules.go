@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Pungyeon/required/pkg/validate"
+)
+
+// constrain will add the keywords equivalent to the given validate rules to
+// the schema of a value of the given type
+func constrain(s *Schema, rules validate.Rules, t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for _, r := range rules.List() {
+		if err := constrainRule(s, r, t.Kind()); err != nil {
+			return err
+		}
+	}
+	dive, ok := rules.Dive()
+	if !ok {
+		return nil
+	}
+	switch {
+	case s.Items != nil:
+		return constrain(s.Items, dive, t.Elem())
+	case s.AdditionalProperties != nil:
+		return constrain(s.AdditionalProperties, dive, t.Elem())
+	}
+	return nil
+}
+
+func constrainRule(s *Schema, r validate.Rule, kind reflect.Kind) error {
+	switch r.Name {
+	case "min", "max", "len":
+		return constrainSize(s, r, kind)
+	case "gt", "gte", "lt", "lte":
+		n, err := strconv.ParseFloat(r.Param, 64)
+		if err != nil {
+			return err
+		}
+		switch r.Name {
+		case "gt":
+			s.ExclusiveMinimum = &n
+		case "gte":
+			s.Minimum = &n
+		case "lt":
+			s.ExclusiveMaximum = &n
+		case "lte":
+			s.Maximum = &n
+		}
+	case "oneof":
+		for _, option := range strings.Fields(r.Param) {
+			if isNumber(kind) {
+				n, err := strconv.ParseFloat(option, 64)
+				if err != nil {
+					return err
+				}
+				s.Enum = append(s.Enum, n)
+			} else {
+				s.Enum = append(s.Enum, option)
+			}
+		}
+	case "regexp":
+		s.Pattern = r.Param
+	case "email":
+		s.Format = "email"
+	case "url":
+		s.Format = "uri"
+	case "uuid":
+		s.Format = "uuid"
+	case "ip":
+		s.AnyOf = append(s.AnyOf, &Schema{Format: "ipv4"}, &Schema{Format: "ipv6"})
+	default:
+		return fmt.Errorf("%w: %s", validate.ErrIllegalRule, r.Name)
+	}
+	return nil
+}
+
+func constrainSize(s *Schema, r validate.Rule, kind reflect.Kind) error {
+	if isNumber(kind) {
+		n, err := strconv.ParseFloat(r.Param, 64)
+		if err != nil {
+			return err
+		}
+		if r.Name != "max" {
+			s.Minimum = &n
+		}
+		if r.Name != "min" {
+			s.Maximum = &n
+		}
+		return nil
+	}
+
+	n, err := strconv.Atoi(r.Param)
+	if err != nil {
+		return err
+	}
+	var min, max **int
+	switch kind {
+	case reflect.String:
+		min, max = &s.MinLength, &s.MaxLength
+	case reflect.Slice, reflect.Array:
+		min, max = &s.MinItems, &s.MaxItems
+	case reflect.Map:
+		min, max = &s.MinProperties, &s.MaxProperties
+	default:
+		return nil
+	}
+	if r.Name != "max" {
+		*min = intPtr(n)
+	}
+	if r.Name != "min" {
+		*max = intPtr(n)
+	}
+	return nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
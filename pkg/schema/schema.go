@@ -0,0 +1,99 @@
+// Package schema generates JSON Schema (draft 2020-12) documents from Go
+// types, using the same struct tags as the json package:
+//
+//	type User struct {
+//		Name  required.String `json:"name"`
+//		Email string          `json:"email,required" validate:"email"`
+//		Age   *int            `json:"age" validate:"gte=0"`
+//	}
+//
+//	s, err := schema.For[User]()
+//
+// Fields which are required by the `required` option or by using one of the
+// types of the required package are listed in the `required` array, and the
+// rules of the `validate` tag are converted to the equivalent keywords.
+package schema
+
+import (
+	"encoding/json"
+)
+
+// Draft is the URI of the JSON Schema dialect, which is generated
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, or a subschema of a document
+type Schema struct {
+	Schema string             `json:"$schema,omitempty"`
+	Ref    string             `json:"$ref,omitempty"`
+	Defs   map[string]*Schema `json:"$defs,omitempty"`
+
+	Type    Types           `json:"type,omitempty"`
+	Format  string          `json:"format,omitempty"`
+	Enum    []interface{}   `json:"enum,omitempty"`
+	Const   interface{}     `json:"const,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	Properties           map[string]*Schema  `json:"properties,omitempty"`
+	AdditionalProperties *Schema             `json:"additionalProperties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	DependentRequired    map[string][]string `json:"dependentRequired,omitempty"`
+	MinProperties        *int                `json:"minProperties,omitempty"`
+	MaxProperties        *int                `json:"maxProperties,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+	If    *Schema   `json:"if,omitempty"`
+	Then  *Schema   `json:"then,omitempty"`
+}
+
+// schema is used for marshalling a Schema, without calling MarshalJSON
+type schema Schema
+
+// MarshalJSON is an implementation of the json.Marshaler interface, which
+// omits all keywords that have not been set. It ensures that a Schema is
+// marshalled in the same way by this library and the standard library.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(schema(s))
+}
+
+// Types is the value of the type keyword. A single type is marshalled as a
+// string, and several types as an array.
+type Types []string
+
+// MarshalJSON is an implementation of the json.Marshaler interface
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON is an implementation of the json.Unmarshaler interface
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Schemer may be implemented by types, which describe their own JSON Schema,
+// such as types implementing json.Marshaler
+type Schemer interface {
+	JSONSchema() *Schema
+}
@@ -0,0 +1,233 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	pkgjson "github.com/Pungyeon/required/pkg/json"
+	"github.com/Pungyeon/required/pkg/required"
+)
+
+func assertSchema(t *testing.T, s *Schema, expected string) {
+	t.Helper()
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual, wanted interface{}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expected), &wanted); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, wanted) {
+		t.Fatalf("unexpected schema:\n%s", data)
+	}
+}
+
+type Address struct {
+	Street  string  `json:"street,required"`
+	Country string  `json:"country" validate:"len=2"`
+	Zip     *string `json:"zip"`
+}
+
+type Customer struct {
+	ID       required.UUID             `json:"id"`
+	Name     required.String           `json:"name"`
+	Email    string                    `json:"email,required" validate:"email"`
+	Age      *int                      `json:"age" validate:"gte=0,lt=150"`
+	Role     string                    `json:"role,default=user" validate:"oneof=admin user"`
+	Tags     []string                  `json:"tags" validate:"max=3,dive,min=1"`
+	Nickname required.Optional[string] `json:"nickname"`
+	Created  time.Time                 `json:"created"`
+	Billing  Address                   `json:"billing"`
+	Shipping *Address                  `json:"shipping,notnull"`
+	Previous []Address                 `json:"previous"`
+	Referrer *Customer                 `json:"referrer"`
+	Scores   map[string]float64        `json:"scores"`
+	Extra    interface{}               `json:"extra"`
+	internal string
+}
+
+func TestGenerate(t *testing.T) {
+	s, err := For[Customer]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t, s, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {
+			"Address": {
+				"type": "object",
+				"properties": {
+					"street": {"type": "string"},
+					"country": {"type": "string", "minLength": 2, "maxLength": 2},
+					"zip": {"type": ["string", "null"]}
+				},
+				"required": ["street"]
+			}
+		},
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"name": {"type": "string", "minLength": 1},
+			"email": {"type": "string", "format": "email"},
+			"age": {"type": ["integer", "null"], "minimum": 0, "exclusiveMaximum": 150},
+			"role": {"type": "string", "enum": ["admin", "user"], "default": "user"},
+			"tags": {"type": "array", "items": {"type": "string", "minLength": 1}, "maxItems": 3},
+			"nickname": {"type": ["string", "null"]},
+			"created": {"type": "string", "format": "date-time"},
+			"billing": {"$ref": "#/$defs/Address"},
+			"shipping": {"$ref": "#/$defs/Address"},
+			"previous": {"type": "array", "items": {"$ref": "#/$defs/Address"}},
+			"referrer": {"anyOf": [{"$ref": "#"}, {"type": "null"}]},
+			"scores": {"type": "object", "additionalProperties": {"type": "number"}},
+			"extra": {}
+		},
+		"required": ["id", "name", "email"]
+	}`)
+}
+
+func TestGenerateConditions(t *testing.T) {
+	type Order struct {
+		Method   string `json:"method"`
+		Quantity int    `json:"quantity"`
+		Address  string `json:"address,required_if=method:card"`
+		Voucher  string `json:"voucher,required_unless=quantity:1"`
+		City     string `json:"city,required_with=address"`
+		Country  string `json:"country,required_without=city"`
+		Email    string `json:"email,oneof=contact"`
+		Phone    string `json:"phone,oneof=contact"`
+	}
+	s, err := For[Order]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t, s, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"method": {"type": "string"},
+			"quantity": {"type": "integer"},
+			"address": {"type": "string"},
+			"voucher": {"type": "string"},
+			"city": {"type": "string"},
+			"country": {"type": "string"},
+			"email": {"type": "string"},
+			"phone": {"type": "string"}
+		},
+		"dependentRequired": {"address": ["city"]},
+		"allOf": [
+			{
+				"if": {"properties": {"method": {"const": "card"}}, "required": ["method"]},
+				"then": {"required": ["address"]}
+			},
+			{
+				"if": {"not": {"properties": {"quantity": {"const": 1}}, "required": ["quantity"]}},
+				"then": {"required": ["voucher"]}
+			},
+			{
+				"if": {"not": {"required": ["city"]}},
+				"then": {"required": ["country"]}
+			},
+			{"oneOf": [{"required": ["email"]}, {"required": ["phone"]}]}
+		]
+	}`)
+}
+
+type Tree struct {
+	Value    int     `json:"value"`
+	Children []*Node `json:"children"`
+}
+
+type Node struct {
+	Tree *Tree `json:"tree"`
+}
+
+func TestGenerateRecursive(t *testing.T) {
+	s, err := Generate(reflect.TypeOf([]Node{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t, s, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {
+			"Node": {
+				"type": "object",
+				"properties": {
+					"tree": {
+						"type": ["object", "null"],
+						"properties": {
+							"value": {"type": "integer"},
+							"children": {"type": "array", "items": {"anyOf": [{"$ref": "#/$defs/Node"}, {"type": "null"}]}}
+						}
+					}
+				}
+			}
+		},
+		"type": "array",
+		"items": {"$ref": "#/$defs/Node"}
+	}`)
+}
+
+type Color struct {
+	R, G, B uint8
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	return []byte(`"#000000"`), nil
+}
+
+func (c Color) JSONSchema() *Schema {
+	return &Schema{Type: Types{"string"}, Pattern: "^#[0-9a-f]{6}$"}
+}
+
+func TestGenerateSchemer(t *testing.T) {
+	type Theme struct {
+		Background Color  `json:"background"`
+		Foreground *Color `json:"foreground"`
+	}
+	s, err := For[Theme]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSchema(t, s, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"background": {"type": "string", "pattern": "^#[0-9a-f]{6}$"},
+			"foreground": {"type": ["string", "null"], "pattern": "^#[0-9a-f]{6}$"}
+		}
+	}`)
+}
+
+func TestGenerateUnsupported(t *testing.T) {
+	type Callback struct {
+		Func func() `json:"func"`
+	}
+	if _, err := For[Callback](); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatal("expected unsupported type error:", err)
+	}
+}
+
+func TestMarshalSchema(t *testing.T) {
+	s, err := For[Address]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := pkgjson.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(expected) {
+		t.Fatalf("%s != %s", data, expected)
+	}
+}
@@ -0,0 +1,644 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidSchema is returned when compiling a malformed schema
+	ErrInvalidSchema = errors.New("invalid schema")
+	// ErrViolation is matched by every Violation, using errors.Is
+	ErrViolation = errors.New("schema violation")
+)
+
+// maxDepth is the maximum number of nested subschemas, which are applied to
+// a single instance. It guards against references which loop infinitely.
+const maxDepth = 512
+
+// Violation is a single keyword of a schema, which an instance does not
+// satisfy. Both locations are given as JSON Pointers (RFC 6901).
+type Violation struct {
+	InstancePath string
+	SchemaPath   string
+	Message      string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s (schema: %s)", pointerOrRoot(v.InstancePath), v.Message, pointerOrRoot(v.SchemaPath))
+}
+
+// Is will report any Violation as being an ErrViolation
+func (v Violation) Is(target error) bool {
+	return target == ErrViolation
+}
+
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "#"
+	}
+	return pointer
+}
+
+// Violations is returned by Validator.Validate, when a document does not
+// conform to the schema
+type Violations []Violation
+
+func (vs Violations) Error() string {
+	messages := make([]string, len(vs))
+	for i, v := range vs {
+		messages[i] = v.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is will report whether any of the violations match the target
+func (vs Violations) Is(target error) bool {
+	return len(vs) > 0 && target == ErrViolation
+}
+
+// Validator validates JSON documents against a compiled JSON Schema. Only
+// references within the schema document itself are resolved, and the
+// format keyword is treated as an annotation, as is the default of draft
+// 2020-12.
+type Validator struct {
+	root     *node
+	id       string
+	anchors  map[string]*node
+	patterns map[string]*regexp.Regexp
+}
+
+// Compile will parse the given JSON Schema document, and check that all of
+// its references and regular expressions are valid.
+func Compile(data []byte) (*Validator, error) {
+	root, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	v := &Validator{
+		root:     root,
+		anchors:  map[string]*node{},
+		patterns: map[string]*regexp.Regexp{},
+	}
+	if id, ok := root.get("$id"); ok && id.kind == kindString {
+		v.id = strings.TrimSuffix(id.text, "#")
+	}
+	var refs []string
+	if err := v.compile(root, "", &refs); err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if _, err := v.resolve(ref); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// Compile will compile the schema, such that it can be used for validating
+// JSON documents
+func (s *Schema) Compile() (*Validator, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(data)
+}
+
+// subschemas lists the keywords, whose values are a schema, an object of
+// schemas or an array of schemas.
+var (
+	schemaKeywords = []string{
+		"additionalProperties", "propertyNames", "contains", "items", "not",
+		"if", "then", "else", "unevaluatedItems", "unevaluatedProperties",
+	}
+	schemaMapKeywords   = []string{"properties", "patternProperties", "dependentSchemas", "$defs", "definitions"}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+)
+
+// compile will walk every subschema, collecting anchors and references, and
+// compiling regular expressions
+func (v *Validator) compile(s *node, path string, refs *[]string) error {
+	if s.kind == kindBoolean {
+		return nil
+	}
+	if s.kind != kindObject {
+		return fmt.Errorf("%w: %s: schema must be an object or a boolean", ErrInvalidSchema, pointerOrRoot(path))
+	}
+	if anchor, ok := s.get("$anchor"); ok && anchor.kind == kindString {
+		v.anchors[anchor.text] = s
+	}
+	if ref, ok := s.get("$ref"); ok {
+		if ref.kind != kindString {
+			return fmt.Errorf("%w: %s/$ref: must be a string", ErrInvalidSchema, path)
+		}
+		*refs = append(*refs, ref.text)
+	}
+	if pattern, ok := s.get("pattern"); ok {
+		if err := v.compilePattern(pattern, path+"/pattern"); err != nil {
+			return err
+		}
+	}
+	if properties, ok := s.get("patternProperties"); ok && properties.kind == kindObject {
+		for _, m := range properties.members {
+			if err := v.compilePattern(&node{kind: kindString, text: m.key}, path+"/patternProperties"); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, keyword := range schemaKeywords {
+		if sub, ok := s.get(keyword); ok {
+			if err := v.compile(sub, path+"/"+keyword, refs); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range schemaMapKeywords {
+		if sub, ok := s.get(keyword); ok {
+			if sub.kind != kindObject {
+				return fmt.Errorf("%w: %s/%s: must be an object", ErrInvalidSchema, path, keyword)
+			}
+			for _, m := range sub.members {
+				if err := v.compile(m.value, path+"/"+keyword+"/"+escape(m.key), refs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, keyword := range schemaArrayKeywords {
+		if sub, ok := s.get(keyword); ok {
+			if sub.kind != kindArray {
+				return fmt.Errorf("%w: %s/%s: must be an array", ErrInvalidSchema, path, keyword)
+			}
+			for i, item := range sub.items {
+				if err := v.compile(item, path+"/"+keyword+"/"+strconv.Itoa(i), refs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Validator) compilePattern(pattern *node, path string) error {
+	if pattern.kind != kindString {
+		return fmt.Errorf("%w: %s: must be a string", ErrInvalidSchema, path)
+	}
+	if _, ok := v.patterns[pattern.text]; ok {
+		return nil
+	}
+	re, err := regexp.Compile(pattern.text)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidSchema, path, err)
+	}
+	v.patterns[pattern.text] = re
+	return nil
+}
+
+// match returns whether the given string matches the pattern. Patterns are
+// compiled along with the schema, but subschemas which can only be reached
+// through a reference into an unknown keyword are compiled here.
+func (v *Validator) match(pattern, s string) bool {
+	re, ok := v.patterns[pattern]
+	if !ok {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(s)
+}
+
+// resolve returns the subschema referred to by the given reference, which
+// must be a fragment of this document: either a JSON Pointer or an anchor.
+func (v *Validator) resolve(ref string) (*node, error) {
+	if v.id != "" && strings.HasPrefix(ref, v.id) {
+		ref = ref[len(v.id):]
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("%w: only local references are supported: %s", ErrInvalidSchema, ref)
+	}
+	fragment := ref[1:]
+	if fragment != "" && fragment[0] != '/' {
+		s, ok := v.anchors[fragment]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown anchor: %s", ErrInvalidSchema, ref)
+		}
+		return s, nil
+	}
+
+	s := v.root
+	for _, token := range splitPointer(fragment) {
+		switch s.kind {
+		case kindObject:
+			next, ok := s.get(token)
+			if !ok {
+				return nil, fmt.Errorf("%w: unresolved reference: %s", ErrInvalidSchema, ref)
+			}
+			s = next
+		case kindArray:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(s.items) {
+				return nil, fmt.Errorf("%w: unresolved reference: %s", ErrInvalidSchema, ref)
+			}
+			s = s.items[i]
+		default:
+			return nil, fmt.Errorf("%w: unresolved reference: %s", ErrInvalidSchema, ref)
+		}
+	}
+	return s, nil
+}
+
+// splitPointer returns the unescaped reference tokens of a JSON Pointer,
+// which may be percent-encoded as a URI fragment
+func splitPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		token = percentDecode(token)
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return tokens
+}
+
+func percentDecode(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// escape returns the given reference token, escaped for a JSON Pointer
+func escape(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// Validate will validate the given JSON document against the schema. If the
+// document does not conform to the schema, all violations are returned as
+// Violations. An error is also returned, if the document is not valid JSON.
+func (v *Validator) Validate(document []byte) error {
+	instance, err := parse(document)
+	if err != nil {
+		return err
+	}
+	c := &check{validator: v}
+	c.validate(v.root, instance, "", "", 0)
+	if len(c.violations) > 0 {
+		return c.violations
+	}
+	return nil
+}
+
+type check struct {
+	validator  *Validator
+	violations Violations
+}
+
+func (c *check) fail(instancePath, schemaPath, format string, args ...interface{}) {
+	c.violations = append(c.violations, Violation{
+		InstancePath: instancePath,
+		SchemaPath:   schemaPath,
+		Message:      fmt.Sprintf(format, args...),
+	})
+}
+
+// valid returns whether the instance is valid against the schema, without
+// recording any violations
+func (c *check) valid(s, instance *node, depth int) bool {
+	sub := &check{validator: c.validator}
+	sub.validate(s, instance, "", "", depth)
+	return len(sub.violations) == 0
+}
+
+func (c *check) validate(s, instance *node, ipath, spath string, depth int) {
+	if depth > maxDepth {
+		c.fail(ipath, spath, "maximum schema depth exceeded")
+		return
+	}
+	depth++
+	if s.kind == kindBoolean {
+		if !s.boolean {
+			c.fail(ipath, spath, "no value is allowed")
+		}
+		return
+	}
+
+	for _, m := range s.members {
+		kpath := spath + "/" + escape(m.key)
+		switch m.key {
+		case "$ref":
+			ref, err := c.validator.resolve(m.value.text)
+			if err != nil {
+				c.fail(ipath, kpath, "%v", err)
+				continue
+			}
+			c.validate(ref, instance, ipath, kpath, depth)
+		case "type":
+			c.checkType(m.value, instance, ipath, kpath)
+		case "enum":
+			c.checkEnum(m.value, instance, ipath, kpath)
+		case "const":
+			if !equal(m.value, instance) {
+				c.fail(ipath, kpath, "must be equal to the constant value")
+			}
+		case "allOf", "anyOf", "oneOf":
+			c.checkCombination(m.key, m.value, instance, ipath, kpath, depth)
+		case "not":
+			if c.valid(m.value, instance, depth) {
+				c.fail(ipath, kpath, "must not be valid against the schema")
+			}
+		case "if":
+			c.checkConditional(s, m.value, instance, ipath, spath, depth)
+		default:
+			switch instance.kind {
+			case kindNumber:
+				c.checkNumber(m, instance, ipath, kpath)
+			case kindString:
+				c.checkString(m, instance, ipath, kpath)
+			case kindArray:
+				c.checkArray(s, m, instance, ipath, kpath, depth)
+			case kindObject:
+				c.checkObject(s, m, instance, ipath, kpath, depth)
+			}
+		}
+	}
+}
+
+func (c *check) checkType(types, instance *node, ipath, spath string) {
+	matches := func(name string) bool {
+		switch name {
+		case "integer":
+			return instance.isInteger()
+		case "number":
+			return instance.kind == kindNumber
+		}
+		return instance.kind.String() == name
+	}
+	if types.kind == kindString {
+		if !matches(types.text) {
+			c.fail(ipath, spath, "expected %s, got %s", types.text, instance.kind)
+		}
+		return
+	}
+	var names []string
+	for _, t := range types.items {
+		if matches(t.text) {
+			return
+		}
+		names = append(names, t.text)
+	}
+	c.fail(ipath, spath, "expected one of %s, got %s", strings.Join(names, ", "), instance.kind)
+}
+
+func (c *check) checkEnum(values, instance *node, ipath, spath string) {
+	for _, value := range values.items {
+		if equal(value, instance) {
+			return
+		}
+	}
+	c.fail(ipath, spath, "must be one of the enumerated values")
+}
+
+func (c *check) checkCombination(keyword string, schemas, instance *node, ipath, spath string, depth int) {
+	switch keyword {
+	case "allOf":
+		for i, s := range schemas.items {
+			c.validate(s, instance, ipath, spath+"/"+strconv.Itoa(i), depth)
+		}
+	case "anyOf":
+		for _, s := range schemas.items {
+			if c.valid(s, instance, depth) {
+				return
+			}
+		}
+		c.fail(ipath, spath, "must be valid against at least one schema")
+	case "oneOf":
+		var matches int
+		for _, s := range schemas.items {
+			if c.valid(s, instance, depth) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			c.fail(ipath, spath, "must be valid against exactly one schema, but is valid against %d", matches)
+		}
+	}
+}
+
+func (c *check) checkConditional(s, condition, instance *node, ipath, spath string, depth int) {
+	keyword := "else"
+	if c.valid(condition, instance, depth) {
+		keyword = "then"
+	}
+	if sub, ok := s.get(keyword); ok {
+		c.validate(sub, instance, ipath, spath+"/"+keyword, depth)
+	}
+}
+
+func (c *check) checkNumber(m member, instance *node, ipath, spath string) {
+	limit := m.value.number
+	n := instance.number
+	switch m.key {
+	case "multipleOf":
+		// allow for the rounding errors of decimal fractions, such as 0.1
+		if q := n / limit; math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9 {
+			c.fail(ipath, spath, "must be a multiple of %s", m.value.text)
+		}
+	case "maximum":
+		if n > limit {
+			c.fail(ipath, spath, "must be less than or equal to %s", m.value.text)
+		}
+	case "exclusiveMaximum":
+		if n >= limit {
+			c.fail(ipath, spath, "must be less than %s", m.value.text)
+		}
+	case "minimum":
+		if n < limit {
+			c.fail(ipath, spath, "must be greater than or equal to %s", m.value.text)
+		}
+	case "exclusiveMinimum":
+		if n <= limit {
+			c.fail(ipath, spath, "must be greater than %s", m.value.text)
+		}
+	}
+}
+
+func (c *check) checkString(m member, instance *node, ipath, spath string) {
+	switch m.key {
+	case "maxLength":
+		if float64(utf8.RuneCountInString(instance.text)) > m.value.number {
+			c.fail(ipath, spath, "length must be at most %s", m.value.text)
+		}
+	case "minLength":
+		if float64(utf8.RuneCountInString(instance.text)) < m.value.number {
+			c.fail(ipath, spath, "length must be at least %s", m.value.text)
+		}
+	case "pattern":
+		if !c.validator.match(m.value.text, instance.text) {
+			c.fail(ipath, spath, "must match the pattern %s", m.value.text)
+		}
+	}
+}
+
+func (c *check) checkArray(s *node, m member, instance *node, ipath, spath string, depth int) {
+	items := instance.items
+	switch m.key {
+	case "maxItems":
+		if float64(len(items)) > m.value.number {
+			c.fail(ipath, spath, "must have at most %s items", m.value.text)
+		}
+	case "minItems":
+		if float64(len(items)) < m.value.number {
+			c.fail(ipath, spath, "must have at least %s items", m.value.text)
+		}
+	case "uniqueItems":
+		if !m.value.boolean {
+			return
+		}
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if equal(items[i], items[j]) {
+					c.fail(ipath, spath, "items %d and %d must be unique", i, j)
+					return
+				}
+			}
+		}
+	case "prefixItems":
+		for i, sub := range m.value.items {
+			if i >= len(items) {
+				break
+			}
+			c.validate(sub, items[i], ipath+"/"+strconv.Itoa(i), spath+"/"+strconv.Itoa(i), depth)
+		}
+	case "items":
+		var start int
+		if prefix, ok := s.get("prefixItems"); ok {
+			start = len(prefix.items)
+		}
+		for i := start; i < len(items); i++ {
+			c.validate(m.value, items[i], ipath+"/"+strconv.Itoa(i), spath, depth)
+		}
+	case "contains":
+		var matches int
+		for _, item := range items {
+			if c.valid(m.value, item, depth) {
+				matches++
+			}
+		}
+		min, max := 1.0, math.Inf(1)
+		if n, ok := s.get("minContains"); ok {
+			min = n.number
+		}
+		if n, ok := s.get("maxContains"); ok {
+			max = n.number
+		}
+		if float64(matches) < min {
+			c.fail(ipath, spath, "must contain at least %v matching items, but contains %d", min, matches)
+		}
+		if float64(matches) > max {
+			c.fail(ipath, spath, "must contain at most %v matching items, but contains %d", max, matches)
+		}
+	}
+}
+
+func (c *check) checkObject(s *node, m member, instance *node, ipath, spath string, depth int) {
+	members := instance.members
+	switch m.key {
+	case "maxProperties":
+		if float64(len(members)) > m.value.number {
+			c.fail(ipath, spath, "must have at most %s properties", m.value.text)
+		}
+	case "minProperties":
+		if float64(len(members)) < m.value.number {
+			c.fail(ipath, spath, "must have at least %s properties", m.value.text)
+		}
+	case "required":
+		for _, name := range m.value.items {
+			if _, ok := instance.get(name.text); !ok {
+				c.fail(ipath, spath, "missing required property: %s", name.text)
+			}
+		}
+	case "dependentRequired":
+		for _, dependency := range m.value.members {
+			if _, ok := instance.get(dependency.key); !ok {
+				continue
+			}
+			for _, name := range dependency.value.items {
+				if _, ok := instance.get(name.text); !ok {
+					c.fail(ipath, spath+"/"+escape(dependency.key), "missing property %s, which is required when %s is present", name.text, dependency.key)
+				}
+			}
+		}
+	case "dependentSchemas":
+		for _, dependency := range m.value.members {
+			if _, ok := instance.get(dependency.key); ok {
+				c.validate(dependency.value, instance, ipath, spath+"/"+escape(dependency.key), depth)
+			}
+		}
+	case "properties":
+		for _, property := range members {
+			if sub, ok := m.value.get(property.key); ok {
+				c.validate(sub, property.value, ipath+"/"+escape(property.key), spath+"/"+escape(property.key), depth)
+			}
+		}
+	case "patternProperties":
+		for _, property := range members {
+			for _, pattern := range m.value.members {
+				if c.validator.match(pattern.key, property.key) {
+					c.validate(pattern.value, property.value, ipath+"/"+escape(property.key), spath+"/"+escape(pattern.key), depth)
+				}
+			}
+		}
+	case "additionalProperties":
+		for _, property := range members {
+			if c.isAdditional(s, property.key) {
+				c.validate(m.value, property.value, ipath+"/"+escape(property.key), spath, depth)
+			}
+		}
+	case "propertyNames":
+		for _, property := range members {
+			name := &node{kind: kindString, text: property.key}
+			c.validate(m.value, name, ipath+"/"+escape(property.key), spath, depth)
+		}
+	}
+}
+
+// isAdditional returns whether the given property is not matched by the
+// properties or patternProperties of the schema
+func (c *check) isAdditional(s *node, key string) bool {
+	if properties, ok := s.get("properties"); ok {
+		if _, ok := properties.get(key); ok {
+			return false
+		}
+	}
+	if patterns, ok := s.get("patternProperties"); ok {
+		for _, pattern := range patterns.members {
+			if c.validator.match(pattern.key, key) {
+				return false
+			}
+		}
+	}
+	return true
+}
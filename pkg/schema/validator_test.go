@@ -0,0 +1,219 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+)
+
+func violations(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var vs Violations
+	if !errors.As(err, &vs) {
+		t.Fatal("unexpected error:", err)
+	}
+	var locations []string
+	for _, v := range vs {
+		locations = append(locations, v.InstancePath+" "+v.SchemaPath)
+	}
+	return locations
+}
+
+func TestValidator(t *testing.T) {
+	tt := []struct {
+		name     string
+		schema   string
+		document string
+		expected []string
+	}{
+		{"true schema", `true`, `{"a": 1}`, nil},
+		{"false schema", `false`, `1`, []string{" "}},
+		{"type", `{"type": "string"}`, `1`, []string{" /type"}},
+		{"integer", `{"type": "integer"}`, `1.0`, nil},
+		{"not integer", `{"type": "integer"}`, `1.5`, []string{" /type"}},
+		{"type array", `{"type": ["string", "null"]}`, `null`, nil},
+		{"enum", `{"enum": [1, "a", {"b": [true]}]}`, `{"b": [true]}`, nil},
+		{"not in enum", `{"enum": [1, "a"]}`, `2`, []string{" /enum"}},
+		{"const", `{"const": 1}`, `1.0`, nil},
+		{"multipleOf", `{"multipleOf": 0.1}`, `0.3`, nil},
+		{"not multipleOf", `{"multipleOf": 2}`, `3`, []string{" /multipleOf"}},
+		{"number limits", `{"minimum": 1, "exclusiveMaximum": 3}`, `3`, []string{" /exclusiveMaximum"}},
+		{"string limits", `{"minLength": 2, "maxLength": 3}`, `"æøåæ"`, []string{" /maxLength"}},
+		{"pattern", `{"pattern": "^[a-z]+$"}`, `"Ab"`, []string{" /pattern"}},
+		{"limits ignore other types", `{"minLength": 2, "minimum": 3, "required": ["a"]}`, `[]`, nil},
+		{
+			"prefixItems and items",
+			`{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}}`,
+			`["a", 1, "b"]`,
+			[]string{"/2 /items/type"},
+		},
+		{"uniqueItems", `{"uniqueItems": true}`, `[1, {"a": 1}, {"a": 1.0}]`, []string{" /uniqueItems"}},
+		{"contains", `{"contains": {"type": "string"}, "maxContains": 1}`, `["a", "b"]`, []string{" /contains"}},
+		{"min items", `{"minItems": 1}`, `[]`, []string{" /minItems"}},
+		{
+			"properties",
+			`{"properties": {"a/b": {"type": "string"}, "c": {"minimum": 1}}, "required": ["c", "d"]}`,
+			`{"a/b": 1, "c": 0}`,
+			[]string{"/a~1b /properties/a~1b/type", "/c /properties/c/minimum", " /required"},
+		},
+		{
+			"additionalProperties",
+			`{"properties": {"a": true}, "patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`,
+			`{"a": 1, "x-b": 2, "c": 3}`,
+			[]string{"/x-b /patternProperties/^x-/type", "/c /additionalProperties"},
+		},
+		{"propertyNames", `{"propertyNames": {"maxLength": 2}}`, `{"ab": 1, "abc": 2}`, []string{"/abc /propertyNames/maxLength"}},
+		{
+			"dependentRequired",
+			`{"dependentRequired": {"street": ["city"]}}`,
+			`{"street": "Dingvej"}`,
+			[]string{" /dependentRequired/street"},
+		},
+		{
+			"dependentSchemas",
+			`{"dependentSchemas": {"card": {"required": ["address"]}}}`,
+			`{"card": 1}`,
+			[]string{" /dependentSchemas/card/required"},
+		},
+		{"allOf", `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, `3`, []string{" /allOf/1/maximum"}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "null"}]}`, `1`, []string{" /anyOf"}},
+		{"oneOf", `{"oneOf": [{"minimum": 1}, {"maximum": 2}]}`, `1.5`, []string{" /oneOf"}},
+		{"not", `{"not": {"type": "string"}}`, `"a"`, []string{" /not"}},
+		{
+			"if then",
+			`{"if": {"properties": {"method": {"const": "card"}}}, "then": {"required": ["address"]}, "else": {"required": ["voucher"]}}`,
+			`{"method": "card"}`,
+			[]string{" /then/required"},
+		},
+		{
+			"if else",
+			`{"if": {"properties": {"method": {"const": "card"}}}, "then": {"required": ["address"]}, "else": {"required": ["voucher"]}}`,
+			`{"method": "cash"}`,
+			[]string{" /else/required"},
+		},
+		{
+			"ref",
+			`{"$defs": {"positive": {"minimum": 0}}, "properties": {"a": {"$ref": "#/$defs/positive"}}}`,
+			`{"a": -1}`,
+			[]string{"/a /properties/a/$ref/minimum"},
+		},
+		{
+			"recursive ref",
+			`{"properties": {"value": {"type": "integer"}, "next": {"$ref": "#"}}}`,
+			`{"value": 1, "next": {"value": 2, "next": {"value": "3"}}}`,
+			[]string{"/next/next/value /properties/next/$ref/properties/next/$ref/properties/value/type"},
+		},
+		{
+			"anchor",
+			`{"$defs": {"name": {"$anchor": "name", "minLength": 1}}, "items": {"$ref": "#name"}}`,
+			`["a", ""]`,
+			[]string{"/1 /items/$ref/minLength"},
+		},
+		{
+			"ref with id",
+			`{"$id": "https://example.com/schema", "$defs": {"a": {"type": "string"}}, "$ref": "https://example.com/schema#/$defs/a"}`,
+			`1`,
+			[]string{" /$ref/type"},
+		},
+		{"infinite ref", `{"$defs": {"a": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`, `1`, nil},
+		{"format is an annotation", `{"format": "email"}`, `"ding"`, nil},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			v, err := Compile([]byte(tf.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = v.Validate([]byte(tf.document))
+			if tf.name == "infinite ref" {
+				if !errors.Is(err, ErrViolation) {
+					t.Fatal("expected maximum depth violation:", err)
+				}
+				return
+			}
+			locations := violations(t, err)
+			if len(locations) != len(tf.expected) {
+				t.Fatalf("unexpected violations: %q: %v", locations, err)
+			}
+			for i := range locations {
+				if locations[i] != tf.expected[i] {
+					t.Fatalf("unexpected violations: %q: %v", locations, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, schema := range []string{
+		`{"$ref": "https://example.com/other.json"}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"$ref": "#missing"}`,
+		`{"pattern": "["}`,
+		`{"properties": {"a": 1}}`,
+		`{"allOf": {}}`,
+		`{"type": `,
+	} {
+		if _, err := Compile([]byte(schema)); !errors.Is(err, ErrInvalidSchema) {
+			t.Fatal("expected invalid schema error:", schema, err)
+		}
+	}
+}
+
+func TestValidateInvalidDocument(t *testing.T) {
+	v, err := Compile([]byte(`true`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, document := range []string{``, `{"a": }`, `[1, 2,]`, `{} {}`} {
+		if err := v.Validate([]byte(document)); err == nil || errors.Is(err, ErrViolation) {
+			t.Fatal("expected syntax error:", document, err)
+		}
+	}
+}
+
+func TestValidateGeneratedSchema(t *testing.T) {
+	s, err := For[Customer]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := s.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := `{
+		"id": "0b3d6d43-39b1-4cbb-8f3a-6e4ba0c6c0e4",
+		"name": "lasse",
+		"email": "lasse@jakobsen.dev",
+		"billing": {"street": "Dingvej 1", "country": "DK"},
+		"referrer": {"id": "0b3d6d43-39b1-4cbb-8f3a-6e4ba0c6c0e4", "name": "ding", "email": "ding@dong.dk"}
+	}`
+	if err := v.Validate([]byte(valid)); err != nil {
+		t.Fatal(err)
+	}
+	invalid := `{
+		"name": "",
+		"email": "lasse@jakobsen.dev",
+		"age": -1,
+		"previous": [{"country": "DNK"}]
+	}`
+	expected := []string{
+		"/name /properties/name/minLength",
+		"/age /properties/age/minimum",
+		"/previous/0/country /properties/previous/items/$ref/properties/country/maxLength",
+		"/previous/0 /properties/previous/items/$ref/required",
+		" /required",
+	}
+	locations := violations(t, v.Validate([]byte(invalid)))
+	if len(locations) != len(expected) {
+		t.Fatalf("unexpected violations: %q", locations)
+	}
+	for i := range expected {
+		if locations[i] != expected[i] {
+			t.Fatalf("unexpected violations: %q", locations)
+		}
+	}
+}
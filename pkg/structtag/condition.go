@@ -0,0 +1,228 @@
+package structtag
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConditionKind describes when a Condition makes its field required
+type ConditionKind uint8
+
+const (
+	// RequiredIf requires the field, if the other field is present with
+	// the given value
+	RequiredIf ConditionKind = iota
+	// RequiredUnless requires the field, unless the other field is present
+	// with the given value
+	RequiredUnless
+	// RequiredWith requires the field, if the other field is present
+	RequiredWith
+	// RequiredWithout requires the field, if the other field is not present
+	RequiredWithout
+)
+
+func (kind ConditionKind) String() string {
+	switch kind {
+	case RequiredIf:
+		return "required_if"
+	case RequiredUnless:
+		return "required_unless"
+	case RequiredWith:
+		return "required_with"
+	case RequiredWithout:
+		return "required_without"
+	}
+	return "unknown"
+}
+
+// Condition makes a field required, depending on the state of another field
+// of the same struct. Fields are referred to by their JSON name.
+type Condition struct {
+	Kind  ConditionKind
+	Field string
+	Value string
+
+	fieldIndex int
+}
+
+func parseCondition(kind ConditionKind, param string) (Condition, error) {
+	c := Condition{Kind: kind, Field: param}
+	if kind == RequiredIf || kind == RequiredUnless {
+		i := strings.IndexByte(param, ':')
+		if i < 0 {
+			return c, fmt.Errorf("illegal tag value: `%s=%s`, expected field:value", kind, param)
+		}
+		c.Field, c.Value = param[:i], param[i+1:]
+	}
+	if c.Field == "" {
+		return c, fmt.Errorf("illegal tag value: `%s=%s`, missing field", kind, param)
+	}
+	return c, nil
+}
+
+// applies returns whether the condition makes its field required, given the
+// decoded struct value and the state of its fields
+func (c Condition) applies(v reflect.Value, state State) bool {
+	present := state[c.fieldIndex] == Present
+	switch c.Kind {
+	case RequiredIf:
+		return present && format(v.Field(c.fieldIndex)) == c.Value
+	case RequiredUnless:
+		return !present || format(v.Field(c.fieldIndex)) != c.Value
+	case RequiredWith:
+		return present
+	case RequiredWithout:
+		return !present
+	}
+	return false
+}
+
+// reason describes why the condition made its field required
+func (c Condition) reason() string {
+	switch c.Kind {
+	case RequiredIf:
+		return fmt.Sprintf("required when %s is %s", c.Field, c.Value)
+	case RequiredUnless:
+		return fmt.Sprintf("required unless %s is %s", c.Field, c.Value)
+	case RequiredWith:
+		return fmt.Sprintf("required when %s is present", c.Field)
+	case RequiredWithout:
+		return fmt.Sprintf("required when %s is missing", c.Field)
+	}
+	return ""
+}
+
+// format returns the textual representation of a field value, which is
+// compared to the value of a condition
+func format(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// GroupKind describes how many fields of a Group must be present
+type GroupKind uint8
+
+const (
+	// OneOf requires exactly one field of the group to be present
+	OneOf GroupKind = iota
+	// AnyOf requires at least one field of the group to be present
+	AnyOf
+)
+
+// Group is a named set of fields, of which one or more must be present. A
+// field is added to a group with the `oneof=name` or `anyof=name` options.
+type Group struct {
+	Kind   GroupKind
+	Name   string
+	Fields []string
+
+	fieldIndexes []int
+}
+
+func (g Group) check(state State) error {
+	var present []string
+	for i, index := range g.fieldIndexes {
+		if state[index] == Present {
+			present = append(present, g.Fields[i])
+		}
+	}
+	fields := strings.Join(g.Fields, ", ")
+	switch {
+	case len(present) == 0 && g.Kind == OneOf:
+		return requiredErr{
+			err:    errRequiredField,
+			field:  fields,
+			reason: "exactly one must be present",
+		}
+	case len(present) == 0:
+		return requiredErr{
+			err:    errRequiredField,
+			field:  fields,
+			reason: "at least one must be present",
+		}
+	case len(present) > 1 && g.Kind == OneOf:
+		return requiredErr{
+			err:    errExclusiveFields,
+			field:  strings.Join(present, ", "),
+			reason: "only one of " + fields + " may be present",
+		}
+	}
+	return nil
+}
+
+// resolve will look up the fields referred to by conditions and groups,
+// returning an error if any of them do not exist
+func (tags *Tags) resolve() error {
+	groups := map[string]*Group{}
+	for key, tag := range tags.Tags {
+		for i, c := range tag.Conditions {
+			other, ok := tags.Tags[c.Field]
+			if !ok {
+				return fmt.Errorf("%s: %s refers to unknown field: %s", key, c.Kind, c.Field)
+			}
+			tag.Conditions[i].fieldIndex = other.FieldIndex
+		}
+		for _, member := range []struct {
+			name string
+			kind GroupKind
+		}{{tag.OneOf, OneOf}, {tag.AnyOf, AnyOf}} {
+			if member.name == "" {
+				continue
+			}
+			g, ok := groups[member.name]
+			if !ok {
+				g = &Group{Kind: member.kind, Name: member.name}
+				groups[member.name] = g
+			}
+			if g.Kind != member.kind {
+				return fmt.Errorf("%s: group %s is used both as oneof and anyof", key, member.name)
+			}
+			g.Fields = append(g.Fields, key)
+			g.fieldIndexes = append(g.fieldIndexes, tag.FieldIndex)
+		}
+	}
+	for _, g := range groups {
+		sort.Sort(byFieldIndex(*g))
+		tags.Groups = append(tags.Groups, *g)
+	}
+	sort.Slice(tags.Groups, func(i, j int) bool {
+		return tags.Groups[i].Name < tags.Groups[j].Name
+	})
+	return nil
+}
+
+type byFieldIndex Group
+
+func (g byFieldIndex) Len() int { return len(g.Fields) }
+func (g byFieldIndex) Less(i, j int) bool {
+	return g.fieldIndexes[i] < g.fieldIndexes[j]
+}
+func (g byFieldIndex) Swap(i, j int) {
+	g.Fields[i], g.Fields[j] = g.Fields[j], g.Fields[i]
+	g.fieldIndexes[i], g.fieldIndexes[j] = g.fieldIndexes[j], g.fieldIndexes[i]
+}
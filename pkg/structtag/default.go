@@ -0,0 +1,44 @@
+package structtag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// defaultJSON will convert the literal of a `default` option to the JSON
+// representation of the value, which is decoded into absent fields of the
+// given type. Numbers, booleans and JSON arrays or objects are given as JSON
+// literals, strings may be given without quotes, and durations such as 5s
+// are converted to their number of nanoseconds.
+func defaultJSON(literal []byte, t reflect.Type) ([]byte, error) {
+	elem := t
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	var candidates [][]byte
+	if elem == durationType {
+		if d, err := time.ParseDuration(string(literal)); err == nil {
+			candidates = append(candidates, []byte(strconv.FormatInt(int64(d), 10)))
+		}
+	}
+	if json.Valid(literal) {
+		candidates = append(candidates, literal)
+	}
+	quoted, err := json.Marshal(string(literal))
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, quoted)
+
+	for _, candidate := range candidates {
+		if err := json.Unmarshal(candidate, reflect.New(t).Interface()); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, fmt.Errorf("illegal default value for %v: `%s`", t, literal)
+}
@@ -6,7 +6,9 @@ import (
 )
 
 var (
-	errRequiredField = errors.New("RequiredInterface field missing")
+	errRequiredField   = errors.New("RequiredInterface field missing")
+	errNullField       = errors.New("field must not be null")
+	errExclusiveFields = errors.New("fields are mutually exclusive")
 )
 
 func IsRequiredErr(err error) bool {
@@ -15,11 +17,15 @@ func IsRequiredErr(err error) bool {
 }
 
 type requiredErr struct {
-	err   error
-	field string
+	err    error
+	field  string
+	reason string
 }
 
 func (err requiredErr) Error() string {
+	if err.reason != "" {
+		return fmt.Sprintf("%v: %s (%s)", err.err, err.field, err.reason)
+	}
 	return fmt.Sprintf("%v: %s", err.err, err.field)
 }
 
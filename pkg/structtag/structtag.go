@@ -2,6 +2,9 @@ package structtag
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/Pungyeon/required/pkg/validate"
 )
 
 type Tag struct {
@@ -9,7 +12,13 @@ type Tag struct {
 	FieldName         string
 	Required          bool
 	OmitIfEmpty       bool
-	IsSet             bool
+	NotNull           bool
+	Conditions        []Condition
+	OneOf             string
+	AnyOf             string
+	HasDefault        bool
+	Default           []byte
+	Rules             validate.Rules
 	RequiredInterface bool
 }
 
@@ -18,46 +27,103 @@ func (t *Tag) addValue(value string) error {
 		t.FieldName = value
 		return nil
 	}
-	switch value {
+	name, param := value, ""
+	if i := strings.IndexByte(value, '='); i >= 0 {
+		name, param = value[:i], value[i+1:]
+	}
+	switch name {
 	case "required":
 		t.Required = true
 	case "omitifempty":
 		t.OmitIfEmpty = true
+	case "notnull":
+		t.NotNull = true
+	case "required_if":
+		return t.addCondition(RequiredIf, param)
+	case "required_unless":
+		return t.addCondition(RequiredUnless, param)
+	case "required_with":
+		return t.addCondition(RequiredWith, param)
+	case "required_without":
+		return t.addCondition(RequiredWithout, param)
+	case "oneof":
+		t.OneOf = param
+	case "anyof":
+		t.AnyOf = param
+	case "default":
+		t.HasDefault = true
+		t.Default = []byte(param)
 	default:
 		return fmt.Errorf("illegal tag value: `%s`", value)
 	}
+	if (name == "oneof" || name == "anyof") && param == "" {
+		return fmt.Errorf("illegal tag value: `%s`, missing group name", value)
+	}
+	return nil
+}
+
+func (t *Tag) addCondition(kind ConditionKind, param string) error {
+	c, err := parseCondition(kind, param)
+	if err != nil {
+		return err
+	}
+	t.Conditions = append(t.Conditions, c)
 	return nil
 }
 
+// Parse will parse the given json struct tag, of the field with the given
+// index. Unlike FromValue, values which depend on the type of the field, such
+// as default values, are not converted.
+func Parse(input string, index int) (Tag, error) {
+	return fromString(input, index)
+}
+
 func fromString(input string, index int) (Tag, error) {
 	tag := Tag{
 		FieldIndex: index,
 	}
-	var previous int
-	current := indexOfNextTag(input, 0)
-	for current < len(input) {
-		if input[current] == ',' {
-			if err := tag.addValue(input[previous:current]); err != nil {
-				return tag, err
-			}
-			current = indexOfNextTag(input, current)
-			previous = current
+	for _, value := range splitTag(input) {
+		if err := tag.addValue(value); err != nil {
+			return tag, err
 		}
-		current++
 	}
-	if previous != current {
-		return tag, tag.addValue(input[previous:current])
+	if tag.Required && tag.HasDefault {
+		return tag, fmt.Errorf("illegal tag: `%s`, a required field cannot have a default value", input)
 	}
 	return tag, nil
 }
 
-func indexOfNextTag(input string, current int) int {
-	for input[current] == ' ' ||
-		input[current] == '\n' ||
-		input[current] == '\t' ||
-		input[current] == ',' ||
-		input[current] == '\r' {
-		current++
+// splitTag will split the given tag on commas, which are not part of a JSON
+// array, object or string, such that default values may contain commas.
+// Surrounding whitespace and empty values are removed.
+func splitTag(input string) []string {
+	var (
+		values   []string
+		previous int
+		depth    int
+		inString bool
+	)
+	add := func(value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	for current := 0; current < len(input); current++ {
+		switch c := input[current]; {
+		case inString && c == '\\':
+			current++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth <= 0:
+			add(input[previous:current])
+			previous = current + 1
+		}
 	}
-	return current
+	add(input[previous:])
+	return values
 }
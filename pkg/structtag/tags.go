@@ -2,90 +2,255 @@ package structtag
 
 import (
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"sync"
 
-	"github.com/Pungyeon/required/pkg/required"
+	"github.com/Pungyeon/required/pkg/validate"
 )
 
-// TODO : @pungyeon - This is currently not thread safe. A mutex lock or channel is therefore needed, to ensure no race conditions are met. The reason for this cache implementation, is for general performance. This accounts for a lot of allocations, and since this is static on compilation, we can guarantee that this will never change. Therefore, the cache is a good place to start.
-var cache = map[reflect.Type]Tags{}
+// cache holds the Tags of every type seen so far, as a reflect.Type to Tags
+// map. Tags are static, so a type is only ever parsed once, which accounts
+// for a lot of saved allocations. A sync.Map is used, as types are decoded
+// concurrently, and entries are written once but read many times.
+var cache sync.Map
+
+// requiredInterface is the required.Required interface, which cannot be
+// imported, as the required package depends on this package
+type requiredInterface interface {
+	IsValueValid() error
+}
+
+// decoderInterface is the required.Decoder interface, which cannot be
+// imported for the same reason
+type decoderInterface interface {
+	DecodeValue(null bool) interface{}
+}
 
 type Tags struct {
+	DecoderInterface   bool
 	RequiredInterface  bool
 	UnmarshalInterface bool
+	ValidatorInterface bool
 	Tags               map[string]Tag
+	Groups             []Group
+	Defaults           []string
+	numField           int
+	order              []string
 }
 
-func (tags Tags) Set(tag Tag) {
-	tag.IsSet = true
-	tags.Tags[tag.FieldName] = tag
+// FieldState describes whether a struct field was absent from the object
+// being decoded, explicitly null, or present with a value.
+type FieldState uint8
+
+const (
+	Absent FieldState = iota
+	Null
+	Present
+)
+
+// State records the FieldState of every field of a struct, for a single
+// decode. As Tags are cached and shared, the state of a decode must never
+// be stored on the Tags themselves.
+type State []FieldState
+
+// Keys returns the JSON names of the fields, in the order of the fields
+func (tags Tags) Keys() []string {
+	return tags.order
 }
 
-func (tags Tags) CheckRequired() error {
-	for _, tag := range tags.Tags {
-		if tag.Required && !tag.IsSet {
+// NewState returns a State in which every field is Absent.
+func (tags Tags) NewState() State {
+	return make(State, tags.numField)
+}
+
+// Set will mark the field described by the given tag with the given state
+func (state State) Set(tag Tag, fs FieldState) {
+	state[tag.FieldIndex] = fs
+}
+
+// CheckRequired will return an error, if any of the required fields have
+// not been marked as Present in the given state, or if any of the notnull
+// fields have been marked as Null. Conditional requirements and groups are
+// evaluated against the given decoded struct value.
+func (tags Tags) CheckRequired(v reflect.Value, state State) error {
+	if errs := tags.Check(v, state); len(errs) > 0 {
+		return errs[0].Err
+	}
+	return nil
+}
+
+// Check will check the same requirements as CheckRequired, but returns an
+// error for every field which does not meet its requirements, in the order
+// of the struct fields. The path of each error is the JSON name of the field,
+// or empty for the errors of groups.
+func (tags Tags) Check(v reflect.Value, state State) validate.Errors {
+	var errs validate.Errors
+	for _, key := range tags.order {
+		tag := tags.Tags[key]
+		if err := tag.check(v, state); err != nil {
+			errs = append(errs, validate.FieldError{Path: key, Err: err})
+		}
+	}
+	for _, g := range tags.Groups {
+		if err := g.check(state); err != nil {
+			errs = append(errs, validate.FieldError{Err: err})
+		}
+	}
+	return errs
+}
+
+func (tag Tag) check(v reflect.Value, state State) error {
+	if tag.Required && state[tag.FieldIndex] != Present {
+		return requiredErr{
+			err:   errRequiredField,
+			field: tag.FieldName,
+		}
+	}
+	if tag.NotNull && state[tag.FieldIndex] == Null {
+		return requiredErr{
+			err:   errNullField,
+			field: tag.FieldName,
+		}
+	}
+	if state[tag.FieldIndex] == Present {
+		return nil
+	}
+	for _, c := range tag.Conditions {
+		if c.applies(v, state) {
 			return requiredErr{
-				err:   errRequiredField,
-				field: tag.FieldName,
+				err:    errRequiredField,
+				field:  tag.FieldName,
+				reason: c.reason(),
 			}
 		}
 	}
 	return nil
 }
 
-func (tags Tags) Reset() {
+// optional is implemented by values such as required.Optional, which
+// distinguish between being absent and being null
+type optional interface {
+	IsAbsent() bool
+	IsNull() bool
+}
+
+// StateOf returns the State of the given struct value, which has not been
+// decoded from JSON. Fields with a zero value are Absent, and all other
+// fields are Present, unless the field itself reports being absent or null.
+func (tags Tags) StateOf(v reflect.Value) State {
+	state := tags.NewState()
 	for _, tag := range tags.Tags {
-		tag.IsSet = false
+		field := v.Field(tag.FieldIndex)
+		switch {
+		case field.CanInterface() && field.Type().Implements(optionalType):
+			o := field.Interface().(optional)
+			if o.IsNull() {
+				state.Set(tag, Null)
+			} else if !o.IsAbsent() {
+				state.Set(tag, Present)
+			}
+		case !field.IsZero():
+			state.Set(tag, Present)
+		}
 	}
+	return state
 }
 
+var (
+	optionalType          = reflect.TypeOf((*optional)(nil)).Elem()
+	requiredInterfaceType = reflect.TypeOf((*requiredInterface)(nil)).Elem()
+	decoderInterfaceType  = reflect.TypeOf((*decoderInterface)(nil)).Elem()
+	unmarshalerType       = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	validatorType         = reflect.TypeOf((*validate.Validator)(nil)).Elem()
+)
+
 func FromValue(vo reflect.Value) (Tags, error) {
-	to := vo.Type()
-	if tags, ok := cache[to]; ok {
-		tags.Reset()
-		return tags, nil
+	key := vo.Type()
+	if tags, ok := cache.Load(key); ok {
+		return tags.(Tags), nil
 	}
 
+	to := key
 	tags := Tags{Tags: make(map[string]Tag)}
-	if vo.CanSet() {
-		if vo.CanAddr() {
-			_, tags.RequiredInterface = vo.Addr().Interface().(required.Required)
-			_, tags.UnmarshalInterface = vo.Addr().Interface().(json.Unmarshaler)
-		} else {
-			_, tags.RequiredInterface = vo.Interface().(required.Required)
-			_, tags.UnmarshalInterface = vo.Interface().(json.Unmarshaler)
-		}
-	}
+	// the interfaces are checked on the pointer type, which has the methods of
+	// both value and pointer receivers, as the Tags are cached for the type,
+	// and must not depend on whether the given value is settable
+	ptr := reflect.PtrTo(key)
+	tags.DecoderInterface = ptr.Implements(decoderInterfaceType)
+	tags.RequiredInterface = ptr.Implements(requiredInterfaceType)
+	tags.UnmarshalInterface = ptr.Implements(unmarshalerType)
+	tags.ValidatorInterface = ptr.Implements(validatorType)
 	if to.Kind() == reflect.Ptr {
 		to = to.Elem()
 	}
 	if to.Kind() != reflect.Struct {
-		cache[to] = tags
+		cache.Store(key, tags)
 		return tags, nil
 	}
+	tags.numField = to.NumField()
 
 	for i := 0; i < to.NumField(); i++ {
 		f := to.Field(i)
+		var (
+			tag Tag
+			key string
+		)
 		jsonTag, ok := f.Tag.Lookup("json")
 		if !ok {
-			tags.Tags[toSnakeCase(f.Name)] = Tag{
+			tag = Tag{
 				FieldIndex: i,
 				FieldName:  f.Name,
 			}
+			key = toSnakeCase(f.Name)
 		} else {
-			tag, err := fromString(jsonTag, i)
-			if err != nil {
+			var err error
+			if tag, err = fromString(jsonTag, i); err != nil {
 				return tags, err
 			}
-			tags.Tags[tag.FieldName] = tag
+			key = tag.FieldName
+		}
+		if tag.HasDefault {
+			var err error
+			if tag.Default, err = defaultJSON(tag.Default, f.Type); err != nil {
+				return tags, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			tags.Defaults = append(tags.Defaults, key)
+		}
+		if validateTag, ok := f.Tag.Lookup("validate"); ok {
+			rules, err := validate.Parse(validateTag, f.Type)
+			if err != nil {
+				return tags, fmt.Errorf("%s: %w", f.Name, err)
+			}
+			tag.Rules = rules
 		}
+		tags.Tags[key] = tag
+		tags.order = append(tags.order, key)
 	}
-	cache[to] = tags
+	if err := tags.resolve(); err != nil {
+		return tags, err
+	}
+	cache.Store(key, tags)
 	return tags, nil
 }
 
+// MustFromValue is like FromValue, but panics if the tags are invalid. It is
+// used for initialising package level variables, such as in generated code.
+func MustFromValue(vo reflect.Value) Tags {
+	tags, err := FromValue(vo)
+	if err != nil {
+		panic(err)
+	}
+	return tags
+}
+
 var diff uint8 = 'a' - 'A'
 
+// ToSnakeCase returns the JSON name of a struct field without a json tag
+func ToSnakeCase(s string) string {
+	return toSnakeCase(s)
+}
+
 func toSnakeCase(s string) string {
 	var result string
 	for i := 0; i < len(s); i++ {
@@ -3,6 +3,7 @@ package structtag
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 type TagTest struct {
@@ -48,3 +49,218 @@ func TestToSnakeCase(t *testing.T) {
 		t.Fatal("oh dear", toSnakeCase(camel))
 	}
 }
+
+func TestFromStringWhitespace(t *testing.T) {
+	tag, err := fromString("name, required", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.FieldName != "name" || !tag.Required {
+		t.Fatalf("%+v", tag)
+	}
+}
+
+func TestNotNullTag(t *testing.T) {
+	tag, err := fromString("name,notnull", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.FieldName != "name" || !tag.NotNull || tag.Required {
+		t.Fatalf("%+v", tag)
+	}
+}
+
+func TestConditionTags(t *testing.T) {
+	tag, err := fromString("billing_address,required_if=payment_method:card,required_with=name", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Condition{
+		{Kind: RequiredIf, Field: "payment_method", Value: "card"},
+		{Kind: RequiredWith, Field: "name"},
+	}
+	if !reflect.DeepEqual(tag.Conditions, expected) {
+		t.Fatalf("%+v", tag.Conditions)
+	}
+
+	for _, input := range []string{
+		"address,required_if=payment_method",
+		"address,required_with=",
+		"address,oneof=",
+	} {
+		if _, err := fromString(input, 0); err == nil {
+			t.Fatal("expected error for:", input)
+		}
+	}
+}
+
+func TestConditionUnknownField(t *testing.T) {
+	var v struct {
+		Address string `json:"address,required_with=nothing"`
+	}
+	if _, err := FromValue(reflect.ValueOf(v)); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestCheckRequiredConditions(t *testing.T) {
+	type Order struct {
+		PaymentMethod  string `json:"payment_method"`
+		BillingAddress string `json:"billing_address,required_if=payment_method:card"`
+		Voucher        string `json:"voucher,required_unless=payment_method:card"`
+		Email          string `json:"email,anyof=contact"`
+		Phone          string `json:"phone,anyof=contact"`
+		Street         string `json:"street,oneof=location"`
+		Coordinates    string `json:"coordinates,oneof=location"`
+		City           string `json:"city,required_with=street"`
+		Country        string `json:"country,required_without=coordinates"`
+	}
+
+	tags, err := FromValue(reflect.ValueOf(Order{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		name    string
+		present []string
+		method  string
+		err     string
+	}{
+		{
+			name:    "card with billing address",
+			present: []string{"payment_method", "billing_address", "email", "coordinates"},
+			method:  "card",
+		},
+		{
+			name:    "card without billing address",
+			present: []string{"payment_method", "email", "coordinates"},
+			method:  "card",
+			err:     "RequiredInterface field missing: billing_address (required when payment_method is card)",
+		},
+		{
+			name:    "cash without voucher",
+			present: []string{"payment_method", "email", "coordinates"},
+			method:  "cash",
+			err:     "RequiredInterface field missing: voucher (required unless payment_method is card)",
+		},
+		{
+			name:    "no contact",
+			present: []string{"payment_method", "billing_address", "coordinates"},
+			method:  "card",
+			err:     "RequiredInterface field missing: email, phone (at least one must be present)",
+		},
+		{
+			name:    "no location",
+			present: []string{"payment_method", "billing_address", "phone", "country"},
+			method:  "card",
+			err:     "RequiredInterface field missing: street, coordinates (exactly one must be present)",
+		},
+		{
+			name:    "both locations",
+			present: []string{"payment_method", "billing_address", "phone", "street", "city", "coordinates"},
+			method:  "card",
+			err:     "fields are mutually exclusive: street, coordinates (only one of street, coordinates may be present)",
+		},
+		{
+			name:    "street without city",
+			present: []string{"payment_method", "billing_address", "phone", "street", "country"},
+			method:  "card",
+			err:     "RequiredInterface field missing: city (required when street is present)",
+		},
+		{
+			name:    "street without country",
+			present: []string{"payment_method", "billing_address", "phone", "street", "city"},
+			method:  "card",
+			err:     "RequiredInterface field missing: country (required when coordinates is missing)",
+		},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			state := tags.NewState()
+			for _, field := range tf.present {
+				state.Set(tags.Tags[field], Present)
+			}
+			err := tags.CheckRequired(reflect.ValueOf(Order{PaymentMethod: tf.method}), state)
+			if tf.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !IsRequiredErr(err) || err.Error() != tf.err {
+				t.Fatal("unexpected error:", err)
+			}
+		})
+	}
+}
+
+func TestSplitTag(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected []string
+	}{
+		{"name,required", []string{"name", "required"}},
+		{" name , omitifempty ,", []string{"name", "omitifempty"}},
+		{"ids,default=[1,2,3]", []string{"ids", "default=[1,2,3]"}},
+		{`labels,default={"a":[1,2],"b":"c,d"},notnull`, []string{"labels", `default={"a":[1,2],"b":"c,d"}`, "notnull"}},
+		{`name,default="ding,\"dong\""`, []string{"name", `default="ding,\"dong\""`}},
+	}
+	for _, tf := range tt {
+		if values := splitTag(tf.input); !reflect.DeepEqual(values, tf.expected) {
+			t.Fatalf("%s: %q", tf.input, values)
+		}
+	}
+}
+
+func TestRequiredDefaultTag(t *testing.T) {
+	if _, err := fromString("page_size,required,default=50", 0); err == nil {
+		t.Fatal("expected error for required field with default value")
+	}
+	if _, err := fromString("page_size,default=50,required", 0); err == nil {
+		t.Fatal("expected error for required field with default value")
+	}
+}
+
+func TestDefaultTags(t *testing.T) {
+	type Query struct {
+		PageSize int               `json:"page_size,default=50"`
+		Sort     string            `json:"sort,default=name"`
+		Number   string            `json:"number,default=50"`
+		Quoted   string            `json:"quoted,default=\"a,b\""`
+		Desc     *bool             `json:"desc,default=true"`
+		Timeout  time.Duration     `json:"timeout,default=5s"`
+		IDs      []int             `json:"ids,default=[1,2,3]"`
+		Labels   map[string]string `json:"labels,default={\"env\":\"dev\"}"`
+	}
+	tags, err := FromValue(reflect.ValueOf(Query{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"page_size": `50`,
+		"sort":      `"name"`,
+		"number":    `"50"`,
+		"quoted":    `"a,b"`,
+		"desc":      `true`,
+		"timeout":   `5000000000`,
+		"ids":       `[1,2,3]`,
+		"labels":    `{"env":"dev"}`,
+	}
+	for key, value := range expected {
+		if tag := tags.Tags[key]; !tag.HasDefault || string(tag.Default) != value {
+			t.Fatalf("%s: %s", key, tag.Default)
+		}
+	}
+	if !reflect.DeepEqual(tags.Defaults, []string{"page_size", "sort", "number", "quoted", "desc", "timeout", "ids", "labels"}) {
+		t.Fatal(tags.Defaults)
+	}
+
+	var invalid struct {
+		PageSize int `json:"page_size,default=fifty"`
+	}
+	if _, err := FromValue(reflect.ValueOf(invalid)); err == nil {
+		t.Fatal("expected error for invalid default value")
+	}
+}
@@ -136,20 +136,23 @@ func init() {
 	TokenTypes[','] = Comma
 	TokenTypes['['] = OpenBrace
 	TokenTypes[']'] = ClosingBrace
-	TokenTypes['('] = OpenBracket
-	TokenTypes[')'] = ClosingBracket
 	TokenTypes['{'] = OpenCurly
 	TokenTypes['}'] = ClosingCurly
-	TokenTypes['.'] = FullStop
 }
 
 var TokenTypes = make([]TokenType, 126)
 
+// TypeOf returns the type of the token consisting of the given single byte
+func TypeOf(b byte) TokenType {
+	if int(b) >= len(TokenTypes) {
+		return Unknown
+	}
+	return TokenTypes[b]
+}
+
 var BraceOpposites = map[byte]byte{
 	'[': ']',
 	']': '[',
-	'(': ')',
-	')': '(',
 	'{': '}',
 	'}': '{',
 }
@@ -162,7 +165,7 @@ type Token struct {
 func NewToken(b []byte, i int) Token {
 	return Token{
 		Value: b[i : i+1], // should we even allocate here?
-		Type:  TokenTypes[b[i]],
+		Type:  TypeOf(b[i]),
 	}
 }
 
@@ -191,8 +194,6 @@ func (token Token) AsValue(vt reflect.Type) (reflect.Value, error) {
 		val.SetFloat(f)
 		return val, err
 	case Boolean:
-		// TODO : this doesn't actually work it will parse any value starting with 'f' and/or 't' as boolean
-		// so "boolean": faulty -> false
 		switch token.Value[0] {
 		case 't':
 			val.SetBool(true)
@@ -203,7 +204,7 @@ func (token Token) AsValue(vt reflect.Type) (reflect.Value, error) {
 		}
 		return val, nil
 	default:
-		return reflect.New(nil), fmt.Errorf("cannot convert token to value: %v", token)
+		return reflect.Value{}, Error(ErrInvalidJSON, fmt.Sprintf("unexpected token: %v", token))
 	}
 }
 
@@ -230,8 +231,6 @@ func (token Token) SetValueOf(val reflect.Value) error {
 		val.SetFloat(f)
 		return err
 	case reflect.Bool:
-		// TODO : this doesn't actually work it will parse any value starting with 'f' and/or 't' as boolean
-		// so "boolean": faulty -> false
 		switch token.Value[0] {
 		case 't':
 			val.SetBool(true)
@@ -259,6 +258,12 @@ func (token Token) ToValue() (reflect.Value, error) {
 	case Integer:
 		val := reflect.New(ReflectTypeInteger).Elem()
 		n, err := strconv.ParseInt(string(token.Value), 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			// as with the standard library, integers which are too large are
+			// decoded as floating point numbers
+			token.Type = Float
+			return token.ToValue()
+		}
 		if err != nil {
 			return val, err
 		}
@@ -273,8 +278,6 @@ func (token Token) ToValue() (reflect.Value, error) {
 		val.SetFloat(f)
 		return val, err
 	case Boolean:
-		// TODO : this doesn't actually work it will parse any value starting with 'f' and/or 't' as boolean
-		// so "boolean": faulty -> false
 		val := reflect.New(ReflectTypeBool).Elem()
 		switch token.Value[0] {
 		case 't':
@@ -286,7 +289,7 @@ func (token Token) ToValue() (reflect.Value, error) {
 		}
 		return val, nil
 	default:
-		return reflect.New(nil), fmt.Errorf("cannot convert token to value: %v", token)
+		return reflect.Value{}, Error(ErrInvalidJSON, fmt.Sprintf("unexpected token: %v", token))
 	}
 }
 
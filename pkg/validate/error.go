@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalid is matched by every FieldError, using errors.Is
+	ErrInvalid = errors.New("validation failed")
+	// ErrIllegalRule is returned when parsing a malformed validate tag
+	ErrIllegalRule = errors.New("illegal validate tag")
+)
+
+// FieldError is the failure of a single rule, at the given JSON path
+type FieldError struct {
+	Path string
+	Rule string
+	Err  error
+}
+
+func (err FieldError) Error() string {
+	path := err.Path
+	if path == "" {
+		path = "$"
+	}
+	if err.Rule == "" {
+		return fmt.Sprintf("%s: %v", path, err.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", path, err.Rule, err.Err)
+}
+
+func (err FieldError) Unwrap() error {
+	return err.Err
+}
+
+// Is will report any FieldError as being an ErrInvalid
+func (err FieldError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Errors is a list of FieldErrors, which is returned when several values
+// have failed validation
+type Errors []FieldError
+
+func (errs Errors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs Errors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// Is reports whether any of the errors match the given target
+func (errs Errors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix will return the errors, with the given path prepended to the path
+// of each error
+func (errs Errors) Prefix(path string) Errors {
+	for i := range errs {
+		errs[i].Path = Join(path, errs[i].Path)
+	}
+	return errs
+}
+
+// Join will join the given JSON paths. Array indexes such as "[1]" are
+// joined without a separator, and fields with a full stop.
+func Join(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	if child == "" {
+		return parent
+	}
+	if child[0] == '[' {
+		return parent + child
+	}
+	return parent + "." + child
+}
+
+// Index returns the JSON path of the array element with the given index
+func Index(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
@@ -0,0 +1,252 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type kinds uint8
+
+const (
+	kindString kinds = 1 << iota
+	kindNumber
+	kindCollection
+
+	kindLength = kindString | kindCollection
+)
+
+func kindOf(t reflect.Type) kinds {
+	switch t.Kind() {
+	case reflect.String:
+		return kindString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kindNumber
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return kindCollection
+	case reflect.Interface:
+		return kindString | kindNumber | kindCollection
+	}
+	return 0
+}
+
+func newRule(name, param string, t reflect.Type) (rule, error) {
+	r := rule{name: name, param: param}
+	var (
+		accepts kinds
+		err     error
+	)
+	switch name {
+	case "min", "max", "len":
+		accepts = kindNumber | kindLength
+		r.check, err = sizeRule(name, param)
+	case "gt", "gte", "lt", "lte":
+		accepts = kindNumber
+		r.check, err = compareRule(name, param)
+	case "oneof":
+		accepts = kindString | kindNumber
+		r.check, err = oneOfRule(param)
+	case "regexp":
+		accepts = kindString
+		r.check, err = regexpRule(param)
+	case "email", "url", "uuid", "ip":
+		accepts = kindString
+		r.check, err = formatRule(name, param)
+	default:
+		return r, fmt.Errorf("%w: unknown rule: %s", ErrIllegalRule, name)
+	}
+	if err != nil {
+		return r, fmt.Errorf("%w: %s: %v", ErrIllegalRule, r, err)
+	}
+	if kindOf(t)&accepts == 0 {
+		return r, fmt.Errorf("%w: %s cannot be applied to %v", ErrIllegalRule, r, t)
+	}
+	return r, nil
+}
+
+// number returns the numeric value of the given value, if it is a number
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// size returns the numeric value of a number, or the length of a string,
+// slice, array or map. The length of a string is given in runes.
+func size(v reflect.Value) (float64, string, error) {
+	if n, ok := number(v); ok {
+		return n, "", nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), "length ", nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), "length ", nil
+	}
+	return 0, "", fmt.Errorf("cannot be applied to %v", v.Type())
+}
+
+func sizeRule(name, param string) (func(reflect.Value) error, error) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) error {
+		n, what, err := size(v)
+		if err != nil {
+			return err
+		}
+		switch {
+		case name == "min" && n < limit:
+			return fmt.Errorf("%smust be at least %s", what, param)
+		case name == "max" && n > limit:
+			return fmt.Errorf("%smust be at most %s", what, param)
+		case name == "len" && n != limit:
+			return fmt.Errorf("%smust be exactly %s", what, param)
+		}
+		return nil
+	}, nil
+}
+
+func compareRule(name, param string) (func(reflect.Value) error, error) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil, err
+	}
+	return func(v reflect.Value) error {
+		n, ok := number(v)
+		if !ok {
+			return fmt.Errorf("cannot be applied to %v", v.Type())
+		}
+		switch {
+		case name == "gt" && !(n > limit):
+			return fmt.Errorf("must be greater than %s", param)
+		case name == "gte" && !(n >= limit):
+			return fmt.Errorf("must be greater than or equal to %s", param)
+		case name == "lt" && !(n < limit):
+			return fmt.Errorf("must be less than %s", param)
+		case name == "lte" && !(n <= limit):
+			return fmt.Errorf("must be less than or equal to %s", param)
+		}
+		return nil
+	}, nil
+}
+
+func oneOfRule(param string) (func(reflect.Value) error, error) {
+	options := strings.Fields(param)
+	if len(options) == 0 {
+		return nil, errors.New("no options given")
+	}
+	return func(v reflect.Value) error {
+		var s string
+		switch v.Kind() {
+		case reflect.String:
+			s = v.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s = strconv.FormatInt(v.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s = strconv.FormatUint(v.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			s = strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		default:
+			return fmt.Errorf("cannot be applied to %v", v.Type())
+		}
+		for _, option := range options {
+			if s == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of: %s", strings.Join(options, ", "))
+	}, nil
+}
+
+func regexpRule(param string) (func(reflect.Value) error, error) {
+	re, err := regexp.Compile(param)
+	if err != nil {
+		return nil, err
+	}
+	return stringRule(func(s string) error {
+		if !re.MatchString(s) {
+			return fmt.Errorf("must match %s", param)
+		}
+		return nil
+	}), nil
+}
+
+func formatRule(name, param string) (func(reflect.Value) error, error) {
+	if param != "" {
+		return nil, errors.New("unexpected parameter")
+	}
+	valid := map[string]func(string) bool{
+		"email": isEmail,
+		"url":   isURL,
+		"uuid":  isUUID,
+		"ip":    isIP,
+	}[name]
+	return stringRule(func(s string) error {
+		if !valid(s) {
+			return fmt.Errorf("must be a valid %s", name)
+		}
+		return nil
+	}), nil
+}
+
+func stringRule(check func(s string) error) func(reflect.Value) error {
+	return func(v reflect.Value) error {
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("cannot be applied to %v", v.Type())
+		}
+		return check(v.String())
+	}
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func isIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// isUUID returns whether the given string is in the canonical textual form
+// of a UUID. Unlike required.UUID, the version and variant are not checked.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			c := s[i]
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+// Package validate implements the declarative validation rules, which may be
+// given using the `validate` struct tag:
+//
+//	type User struct {
+//		Name  string   `json:"name" validate:"min=1,max=64"`
+//		Email string   `json:"email" validate:"email"`
+//		Role  string   `json:"role" validate:"oneof=admin user"`
+//		Tags  []string `json:"tags" validate:"max=10,dive,min=1"`
+//	}
+//
+// Rules are separated by commas, and a comma may be escaped as `\,` in a rule
+// parameter. The `dive` rule will apply all of the following rules to every
+// element of a slice, array or map.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Rules is a parsed validate tag
+type Rules struct {
+	rules []rule
+	dive  *Rules
+}
+
+type rule struct {
+	name  string
+	param string
+	check func(v reflect.Value) error
+}
+
+func (r rule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// Rule is the name and parameter of a single parsed rule, such as min and 1
+// for the rule `min=1`
+type Rule struct {
+	Name  string
+	Param string
+}
+
+// List returns the rules, which apply to the value itself
+func (rules Rules) List() []Rule {
+	list := make([]Rule, len(rules.rules))
+	for i, r := range rules.rules {
+		list[i] = Rule{Name: r.name, Param: r.param}
+	}
+	return list
+}
+
+// Dive returns the rules, which apply to every element of the value, and
+// whether the `dive` rule was given
+func (rules Rules) Dive() (Rules, bool) {
+	if rules.dive == nil {
+		return Rules{}, false
+	}
+	return *rules.dive, true
+}
+
+// IsEmpty returns whether there are no rules
+func (rules Rules) IsEmpty() bool {
+	return len(rules.rules) == 0 && rules.dive == nil
+}
+
+// Parse will parse the given validate tag, for values of the given type. An
+// error is returned, if any of the rules cannot be applied to the type.
+func Parse(tag string, t reflect.Type) (Rules, error) {
+	return parse(split(tag), t)
+}
+
+func parse(values []string, t reflect.Type) (Rules, error) {
+	var rules Rules
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i, value := range values {
+		if value == "dive" {
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array, reflect.Map:
+			default:
+				return rules, fmt.Errorf("%w: dive cannot be applied to %v", ErrIllegalRule, t)
+			}
+			dive, err := parse(values[i+1:], t.Elem())
+			if err != nil {
+				return rules, err
+			}
+			rules.dive = &dive
+			return rules, nil
+		}
+		name, param := value, ""
+		if i := strings.IndexByte(value, '='); i >= 0 {
+			name, param = value[:i], value[i+1:]
+		}
+		r, err := newRule(name, param, t)
+		if err != nil {
+			return rules, err
+		}
+		rules.rules = append(rules.rules, r)
+	}
+	return rules, nil
+}
+
+// split will split the given tag into rules, on every comma which has not
+// been escaped with a backslash
+func split(tag string) []string {
+	var (
+		values  []string
+		current strings.Builder
+	)
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			current.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			values = append(values, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(tag[i])
+		}
+	}
+	values = append(values, current.String())
+
+	var rules []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			rules = append(rules, value)
+		}
+	}
+	return rules
+}
+
+// Validate will validate the given value using the rules, returning an error
+// for every value which fails. Validation of a value stops at the first
+// failing rule. The paths of the errors are relative to the given value, and
+// nil pointers are never validated.
+func (rules Rules) Validate(v reflect.Value) Errors {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	for _, r := range rules.rules {
+		if err := r.check(v); err != nil {
+			return Errors{{Rule: r.String(), Err: err}}
+		}
+	}
+	if rules.dive == nil {
+		return nil
+	}
+
+	var errs Errors
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, rules.dive.Validate(v.Index(i)).Prefix(Index(i))...)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			errs = append(errs, rules.dive.Validate(v.MapIndex(key)).Prefix(fmt.Sprint(key))...)
+		}
+	}
+	return errs
+}
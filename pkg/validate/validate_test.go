@@ -0,0 +1,136 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	tt := []struct {
+		name  string
+		tag   string
+		value interface{}
+		err   bool
+	}{
+		{"min string", "min=3", "abc", false},
+		{"min string too short", "min=3", "ab", true},
+		{"min counts runes", "min=3", "æøå", false},
+		{"max number", "max=10", 10, false},
+		{"max number too large", "max=10", 11, true},
+		{"len slice", "len=2", []int{1, 2}, false},
+		{"len slice wrong length", "len=2", []int{1}, true},
+		{"gt", "gt=0", 0.5, false},
+		{"gt equal", "gt=0", 0, true},
+		{"gte equal", "gte=0", uint(0), false},
+		{"lt", "lt=5", int8(4), false},
+		{"lte too large", "lte=5", 6, true},
+		{"oneof", "oneof=admin user", "user", false},
+		{"oneof missing", "oneof=admin user", "guest", true},
+		{"oneof number", "oneof=1 2 3", 2, false},
+		{"regexp", `regexp=^[a-z]+$`, "lasse", false},
+		{"regexp mismatch", `regexp=^[a-z]+$`, "Lasse", true},
+		{"regexp escaped comma", `regexp=^a{1\,2}$`, "aa", false},
+		{"email", "email", "lasse@jakobsen.dev", false},
+		{"email invalid", "email", "lasse.jakobsen.dev", true},
+		{"email with name", "email", "Lasse <lasse@jakobsen.dev>", true},
+		{"url", "url", "https://github.com/Pungyeon/required", false},
+		{"url without scheme", "url", "github.com/Pungyeon/required", true},
+		{"uuid", "uuid", "0b3d6d43-39b1-4cbb-8f3a-6e4ba0c6c0e4", false},
+		{"uuid invalid", "uuid", "0b3d6d43-39b1-4cbb-8f3a-6e4ba0c6c0e", true},
+		{"ip v4", "ip", "127.0.0.1", false},
+		{"ip v6", "ip", "::1", false},
+		{"ip invalid", "ip", "256.0.0.1", true},
+		{"several rules", "min=1,max=3", "abcd", true},
+		{"dive", "max=3,dive,min=1", []string{"a", "b"}, false},
+		{"dive element", "max=3,dive,min=1", []string{"a", ""}, true},
+		{"dive map", "dive,gt=0", map[string]int{"a": 1}, false},
+		{"pointer", "min=1", func() *string { s := ""; return &s }(), true},
+		{"nil pointer", "min=1", (*string)(nil), false},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			v := reflect.ValueOf(tf.value)
+			rules, err := Parse(tf.tag, v.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			errs := rules.Validate(v)
+			if tf.err != (len(errs) > 0) {
+				t.Fatal("unexpected result:", errs)
+			}
+			if tf.err && !errors.Is(errs, ErrInvalid) {
+				t.Fatal("expected ErrInvalid:", errs)
+			}
+		})
+	}
+}
+
+func TestIllegalRules(t *testing.T) {
+	tt := []struct {
+		name  string
+		tag   string
+		value interface{}
+	}{
+		{"unknown rule", "ding", ""},
+		{"invalid number", "min=ten", ""},
+		{"invalid regexp", "regexp=[", ""},
+		{"empty oneof", "oneof=", ""},
+		{"gt on string", "gt=1", ""},
+		{"email on number", "email", 1},
+		{"email with parameter", "email=true", ""},
+		{"dive on string", "dive,min=1", ""},
+		{"dive with illegal rule", "dive,email", []int{}},
+	}
+
+	for _, tf := range tt {
+		t.Run(tf.name, func(t *testing.T) {
+			if _, err := Parse(tf.tag, reflect.TypeOf(tf.value)); !errors.Is(err, ErrIllegalRule) {
+				t.Fatal("expected ErrIllegalRule:", err)
+			}
+		})
+	}
+}
+
+func TestErrorPaths(t *testing.T) {
+	rules, err := Parse("dive,dive,max=1", reflect.TypeOf([][]int{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := rules.Validate(reflect.ValueOf([][]int{{0, 2}, {3}})).Prefix("matrix")
+	if len(errs) != 2 {
+		t.Fatal("unexpected errors:", errs)
+	}
+	if errs[0].Path != "matrix[0][1]" || errs[1].Path != "matrix[1][0]" {
+		t.Fatal("unexpected paths:", errs)
+	}
+	if errs.Error() != "matrix[0][1]: max=1: must be at most 1; matrix[1][0]: max=1: must be at most 1" {
+		t.Fatal("unexpected message:", errs.Error())
+	}
+}
+
+type period struct {
+	start, end int
+}
+
+func (p period) Validate(ctx *Context) error {
+	if p.start < 0 {
+		ctx.AddError("start", errors.New("must not be negative"))
+	}
+	if p.end < p.start {
+		return Errors{{Path: "end", Err: errors.New("must not be before start")}}
+	}
+	return nil
+}
+
+func TestContext(t *testing.T) {
+	ctx := NewContext("periods[2]")
+	errs := ctx.Run(period{start: -1, end: -2})
+	if errs.Error() != "periods[2].start: must not be negative; periods[2].end: must not be before start" {
+		t.Fatal("unexpected errors:", errs)
+	}
+	if errs := NewContext("").Run(period{start: 1, end: 2}); errs != nil {
+		t.Fatal("unexpected errors:", errs)
+	}
+}
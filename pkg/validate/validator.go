@@ -0,0 +1,60 @@
+package validate
+
+// Validator may be implemented by types, which validate rules spanning
+// several of their fields. Validate is called once the value has been fully
+// decoded, after the values of all of its fields have been validated, so
+// nested values are always validated before the values containing them.
+//
+// Errors may either be added to the context, or returned. A returned error is
+// reported at the path of the value itself.
+type Validator interface {
+	Validate(ctx *Context) error
+}
+
+// Context is given to a Validator, and collects the errors found while
+// validating a value.
+type Context struct {
+	path string
+	errs Errors
+}
+
+// NewContext returns a Context for validating the value at the given path
+func NewContext(path string) *Context {
+	return &Context{path: path}
+}
+
+// Path returns the JSON path of the value being validated. The path of the
+// root value is empty.
+func (ctx *Context) Path() string {
+	return ctx.path
+}
+
+// AddError will add an error for the given field, which is a path relative to
+// the value being validated, such as "end" or "items[1].name".
+func (ctx *Context) AddError(field string, err error) {
+	ctx.errs = append(ctx.errs, FieldError{
+		Path: Join(ctx.path, field),
+		Err:  err,
+	})
+}
+
+// Errors returns the errors, which have been added to the context
+func (ctx *Context) Errors() Errors {
+	return ctx.errs
+}
+
+// Run will call the Validate method of the given validator, and return all
+// errors added to the context along with any returned error.
+func (ctx *Context) Run(v Validator) Errors {
+	if err := v.Validate(ctx); err != nil {
+		switch err := err.(type) {
+		case Errors:
+			ctx.errs = append(ctx.errs, err.Prefix(ctx.path)...)
+		case FieldError:
+			ctx.errs = append(ctx.errs, Errors{err}.Prefix(ctx.path)...)
+		default:
+			ctx.errs = append(ctx.errs, FieldError{Path: ctx.path, Err: err})
+		}
+	}
+	return ctx.errs
+}